@@ -4,8 +4,10 @@ import (
 	"github.com/chloyka/gorig/internal/audio"
 	"github.com/chloyka/gorig/internal/config"
 	"github.com/chloyka/gorig/internal/effects"
+	_ "github.com/chloyka/gorig/internal/effects/builtin"
 	"github.com/chloyka/gorig/internal/logger"
 	"github.com/chloyka/gorig/internal/onset"
+	"github.com/chloyka/gorig/internal/pattern"
 	"github.com/chloyka/gorig/internal/pedal"
 	"github.com/chloyka/gorig/internal/preset"
 	"github.com/chloyka/gorig/internal/rhythm"
@@ -27,6 +29,7 @@ func main() {
 		effects.Module,
 		onset.Module,
 		rhythm.Module,
+		pattern.Module,
 		preset.Module,
 		audio.Module,
 		pedal.Module,
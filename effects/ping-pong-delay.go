@@ -0,0 +1,52 @@
+//go:build ignore
+
+package effects
+
+var Name = "ping pong delay"
+var Enabled = true
+
+var DelaySeconds float32 = 0.333
+var Feedback float32 = 0.45
+var Mix float32 = 0.35
+
+var delaySamples = 16000
+var left = make([]float32, 48000)
+var right = make([]float32, 48000)
+var pos = 0
+
+func SetSampleRate(sampleRate int) {
+	delaySamples = int(DelaySeconds * float32(sampleRate))
+	left = make([]float32, sampleRate)
+	right = make([]float32, sampleRate)
+	pos = 0
+}
+
+func ProcessChannels(channels [][]float32) {
+	in := channels[0]
+	outL := channels[0]
+	outR := channels[0]
+	if len(channels) > 1 {
+		outR = channels[1]
+	}
+
+	for i := range in {
+		read := (pos - delaySamples + len(left)) % len(left)
+		delayedL := left[read]
+		delayedR := right[read]
+
+		dry := in[i]
+		if len(channels) > 1 {
+			dry = (in[i] + channels[1][i]) * 0.5
+		}
+
+		left[pos] = dry + delayedR*Feedback
+		right[pos] = delayedL * Feedback
+
+		outL[i] = outL[i] + delayedL*Mix
+		if len(channels) > 1 {
+			outR[i] = outR[i] + delayedR*Mix
+		}
+
+		pos = (pos + 1) % len(left)
+	}
+}
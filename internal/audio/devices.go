@@ -0,0 +1,112 @@
+package audio
+
+import (
+	"time"
+
+	"github.com/chloyka/gorig/internal/logger/keys"
+	errs "github.com/chloyka/gorig/utils/errors"
+	"github.com/gordonklaus/portaudio"
+)
+
+type DeviceDetails struct {
+	Name              string
+	HostAPI           string
+	MaxInputChannels  int
+	MaxOutputChannels int
+	DefaultSampleRate float64
+
+	LowInputLatency   time.Duration
+	HighInputLatency  time.Duration
+	LowOutputLatency  time.Duration
+	HighOutputLatency time.Duration
+}
+
+func newDeviceDetails(d *portaudio.DeviceInfo) DeviceDetails {
+	details := DeviceDetails{
+		Name:              d.Name,
+		MaxInputChannels:  d.MaxInputChannels,
+		MaxOutputChannels: d.MaxOutputChannels,
+		DefaultSampleRate: d.DefaultSampleRate,
+		LowInputLatency:   d.DefaultLowInputLatency,
+		HighInputLatency:  d.DefaultHighInputLatency,
+		LowOutputLatency:  d.DefaultLowOutputLatency,
+		HighOutputLatency: d.DefaultHighOutputLatency,
+	}
+
+	if d.HostApi != nil {
+		details.HostAPI = d.HostApi.Name
+	}
+
+	return details
+}
+
+func describeDevices(devices []*portaudio.DeviceInfo) []DeviceDetails {
+	details := make([]DeviceDetails, 0, len(devices))
+	for _, d := range devices {
+		details = append(details, newDeviceDetails(d))
+	}
+	return details
+}
+
+func (e *Engine) InputDevices() []DeviceDetails {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return describeDevices(e.inputDevices)
+}
+
+func (e *Engine) OutputDevices() []DeviceDetails {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return describeDevices(e.outputDevices)
+}
+
+func (e *Engine) CurrentDeviceIndexes() (int, int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.inputIndex, e.outputIndex
+}
+
+func (e *Engine) SelectDevices(inputIndex, outputIndex int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if inputIndex < 0 || inputIndex >= len(e.inputDevices) || outputIndex < 0 || outputIndex >= len(e.outputDevices) {
+		return errs.ErrAudioDeviceIndex
+	}
+
+	if inputIndex == e.inputIndex && outputIndex == e.outputIndex {
+		return nil
+	}
+
+	return e.switchDevicesLocked(inputIndex, outputIndex)
+}
+
+func (e *Engine) switchDevicesLocked(inputIndex, outputIndex int) error {
+	e.stopStream()
+
+	e.inputIndex = inputIndex
+	e.outputIndex = outputIndex
+	e.fallback = false
+
+	inputName := e.inputDevices[inputIndex].Name
+	outputName := e.outputDevices[outputIndex].Name
+
+	e.logger.Info("switched devices",
+		keys.DeviceInputName(inputName),
+		keys.DeviceOutputName(outputName),
+	)
+
+	err := e.startStream()
+	if err != nil {
+		e.logger.Error("failed to restart stream", keys.Error(err))
+	}
+
+	if e.stateConfig.InputDevice != inputName {
+		e.stateConfig.SetInputDevice(inputName)
+	}
+	if e.stateConfig.OutputDevice != outputName {
+		e.stateConfig.SetOutputDevice(outputName)
+	}
+
+	return err
+}
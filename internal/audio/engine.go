@@ -1,9 +1,12 @@
 package audio
 
 import (
+	"math"
 	"sync"
+	"time"
 
 	configTypes "github.com/chloyka/gorig/internal/config/types"
+	"github.com/chloyka/gorig/internal/dsp"
 	"github.com/chloyka/gorig/internal/effects"
 	"github.com/chloyka/gorig/internal/logger"
 	"github.com/chloyka/gorig/internal/logger/keys"
@@ -22,6 +25,23 @@ type Engine struct {
 	chain         *effects.Chain
 	onsetDetector *onset.Detector
 	rhythmEngine  *rhythm.Engine
+	fader         *fader
+	monitor       *monitor
+	probe         *latencyProbe
+	inputMeter    *meter
+	outputMeter   *meter
+	inputGain     *gainStage
+	outputVolume  *gainStage
+	statsDone     chan struct{}
+	notices       chan DeviceNotice
+	fallback      bool
+	lost          bool
+	format        StreamFormat
+
+	inputRouting  configTypes.InputRouting
+	outputRouting configTypes.OutputRouting
+	processBuf    *dsp.Buffer
+	onsetBuf      []float32
 
 	inputDevices  []*portaudio.DeviceInfo
 	outputDevices []*portaudio.DeviceInfo
@@ -41,6 +61,16 @@ func newEngine(logger *logger.Logger, chain *effects.Chain, onsetDetector *onset
 		chain:         chain,
 		onsetDetector: onsetDetector,
 		rhythmEngine:  rhythmEngine,
+		processBuf:    dsp.NewBuffer(dsp.MaxChannels, cfg.FramesPerBuffer),
+		onsetBuf:      make([]float32, cfg.FramesPerBuffer),
+		fader:         newFader(cfg.SampleRate),
+		monitor:       &monitor{},
+		probe:         &latencyProbe{},
+		notices:       make(chan DeviceNotice, deviceNoticeBuffer),
+		inputMeter:    newMeter(cfg.SampleRate),
+		outputMeter:   newMeter(cfg.SampleRate),
+		inputGain:     newGainStage(cfg.SampleRate, stateConfig.InputGainDb),
+		outputVolume:  newGainStage(cfg.SampleRate, stateConfig.OutputVolumeDb),
 	}
 
 	if err := e.loadDevices(); err != nil {
@@ -51,6 +81,26 @@ func newEngine(logger *logger.Logger, chain *effects.Chain, onsetDetector *onset
 }
 
 func (e *Engine) loadDevices() error {
+	if err := e.enumerateDevices(); err != nil {
+		return err
+	}
+
+	e.logger.Info("found audio devices",
+		keys.DeviceInputCount(len(e.inputDevices)),
+		keys.DeviceOutputCount(len(e.outputDevices)),
+	)
+
+	e.restoreSavedDevices()
+
+	return nil
+}
+
+func (e *Engine) enumerateDevices() error {
+	e.inputDevices = nil
+	e.outputDevices = nil
+	e.inputIndex = 0
+	e.outputIndex = 0
+
 	devices, err := portaudio.Devices()
 	if err != nil {
 		return errs.Wrap(errs.ErrAudioGetDevices, err)
@@ -74,13 +124,6 @@ func (e *Engine) loadDevices() error {
 		}
 	}
 
-	e.logger.Info("found audio devices",
-		keys.DeviceInputCount(len(e.inputDevices)),
-		keys.DeviceOutputCount(len(e.outputDevices)),
-	)
-
-	e.restoreSavedDevices()
-
 	return nil
 }
 
@@ -92,6 +135,7 @@ func (e *Engine) restoreSavedDevices() {
 			e.inputIndex = idx
 			e.logger.Info("restored saved input device", keys.DeviceName(e.stateConfig.InputDevice))
 		} else {
+			e.fallback = true
 			e.logger.Warn("saved input device not found, using default",
 				keys.DeviceSavedName(e.stateConfig.InputDevice),
 				keys.DeviceUsingName(e.inputDevices[e.inputIndex].Name),
@@ -105,6 +149,7 @@ func (e *Engine) restoreSavedDevices() {
 			e.outputIndex = idx
 			e.logger.Info("restored saved output device", keys.DeviceName(e.stateConfig.OutputDevice))
 		} else {
+			e.fallback = true
 			e.logger.Warn("saved output device not found, using default",
 				keys.DeviceSavedName(e.stateConfig.OutputDevice),
 				keys.DeviceUsingName(e.outputDevices[e.outputIndex].Name),
@@ -126,7 +171,15 @@ func (e *Engine) Start() error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	return e.startStream()
+	if err := e.startStream(); err != nil {
+		return err
+	}
+
+	e.statsDone = make(chan struct{})
+	go e.logStats(e.statsDone)
+	go e.watchDevices(e.statsDone)
+
+	return nil
 }
 
 func (e *Engine) startStream() error {
@@ -142,56 +195,123 @@ func (e *Engine) startStream() error {
 		keys.DeviceOutputName(outputDev.Name),
 	)
 
+	e.inputRouting = e.resolveInputRouting(inputDev)
+	e.outputRouting = e.resolveOutputRouting(outputDev)
+
+	inputChannels := e.inputRouting.ChannelCount()
+	outputChannels := e.outputRouting.ChannelCount()
+
 	streamParams := portaudio.StreamParameters{
 		Input: portaudio.StreamDeviceParameters{
 			Device:   inputDev,
-			Channels: e.cfg.NumChannels,
+			Channels: inputChannels,
 			Latency:  e.cfg.TargetLatency,
 		},
 		Output: portaudio.StreamDeviceParameters{
 			Device:   outputDev,
-			Channels: e.cfg.NumChannels,
+			Channels: outputChannels,
 			Latency:  e.cfg.TargetLatency,
 		},
-		SampleRate:      float64(e.cfg.SampleRate),
-		FramesPerBuffer: e.cfg.FramesPerBuffer,
 	}
 
 	onsetDet := e.onsetDetector
 	rhythmEng := e.rhythmEngine
+	streamFader := e.fader
+	perf := e.monitor
+	probe := e.probe
+	inputMeter := e.inputMeter
+	outputMeter := e.outputMeter
+	inputGain := e.inputGain
+	outputVolume := e.outputVolume
+	inputRouting := e.inputRouting
+	outputTargets := e.outputRouting.Channels
+	processBuf := e.processBuf
+
+	var sampleRate time.Duration
+	var onsetBuf []float32
+
+	stream, format, err := e.openNegotiatedStream(streamParams, func(in, out []float32, _ portaudio.StreamCallbackTimeInfo, flags portaudio.StreamCallbackFlags) {
+		start := time.Now()
+
+		frames := len(in) / inputChannels
+		buf := processBuf
+
+		extractInput(buf, in, inputChannels, inputRouting)
+
+		if probe.running() {
+			probe.process(buf)
+			routeOutput(out, buf, outputChannels, outputTargets)
+			perf.record(time.Since(start), time.Duration(frames)*time.Second/sampleRate, flags)
+			return
+		}
 
-	stream, err := portaudio.OpenStream(streamParams, func(in, out []float32) {
-		copy(out, in)
+		inputMeter.process(buf)
 
 		if onsetDet != nil {
-			onsetDet.Process(in)
+			if buf.Channels() == 1 {
+				onsetDet.Process(buf.Channel(0))
+			} else {
+				for from := 0; from < frames && len(onsetBuf) > 0; from += len(onsetBuf) {
+					chunk := onsetBuf[:min(len(onsetBuf), frames-from)]
+					buf.MixDown(chunk, from)
+					onsetDet.Process(chunk)
+				}
+			}
 		}
 
+		inputGain.process(buf)
+
+		switchAt := -1
+
 		if rhythmEng != nil {
-			if q := rhythmEng.ProcessBuffer(len(in)); q != nil {
+			q := rhythmEng.ProcessBuffer(frames)
+			if q != nil {
 				effects.SetCurrentOnset(true, q.OriginalEvent.Energy, q.BeatPosition, q.SlotIndex)
 			} else {
 				effects.ClearCurrentOnset()
 			}
+			e.chain.SetTransport(rhythmEng.GetBPM(), q != nil)
+
+			if boundary := e.chain.PendingBoundary(); boundary != rhythm.BoundaryNone {
+				switchAt = rhythmEng.BoundaryOffset(boundary)
+			}
 		}
 
-		e.chain.Process(out)
+		e.chain.ProcessSwitching(buf, switchAt)
+
+		outputVolume.process(buf)
+
+		streamFader.process(buf)
+
+		outputMeter.process(buf)
+
+		routeOutput(out, buf, outputChannels, outputTargets)
+
+		perf.record(time.Since(start), time.Duration(frames)*time.Second/sampleRate, flags)
 	})
 	if err != nil {
 		return errs.Wrap(errs.ErrAudioOpenStream, err)
 	}
 
+	e.applyStreamFormat(format)
+	sampleRate = time.Duration(format.SampleRate)
+	onsetBuf = e.onsetBuf
+
 	e.stream = stream
+	streamFader.fadeIn()
 
 	if err = stream.Start(); err != nil {
 		return errs.Wrap(errs.ErrAudioStartStream, err)
 	}
 
 	info := stream.Info()
+	perf.reset(info)
 
 	log := e.logger.With(
-		keys.AudioSampleRate(e.cfg.SampleRate),
-		keys.AudioFramesPerBuffer(e.cfg.FramesPerBuffer),
+		keys.AudioSampleRate(format.SampleRate),
+		keys.AudioFramesPerBuffer(format.FramesPerBuffer),
+		keys.AudioInputChannels(inputChannels),
+		keys.AudioOutputChannels(outputChannels),
 	)
 
 	if info != nil {
@@ -201,6 +321,13 @@ func (e *Engine) startStream() error {
 		)
 	}
 
+	if format.Fallback {
+		log.Warn("configured stream format not supported, using fallback",
+			keys.AudioRequestedSampleRate(e.cfg.SampleRate),
+			keys.AudioRequestedFramesPerBuffer(e.cfg.FramesPerBuffer),
+		)
+	}
+
 	log.Info("audio engine started")
 
 	return nil
@@ -208,6 +335,9 @@ func (e *Engine) startStream() error {
 
 func (e *Engine) stopStream() {
 	if e.stream != nil {
+		e.fader.fadeOut()
+		e.fader.waitSilent()
+
 		if err := e.stream.Stop(); err != nil {
 			e.logger.Warn("failed to stop stream", keys.Error(err))
 		}
@@ -224,48 +354,26 @@ func (e *Engine) NextInputDevice() string {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	if len(e.inputDevices) == 0 {
+	if len(e.inputDevices) == 0 || len(e.outputDevices) == 0 {
 		return ""
 	}
 
-	e.stopStream()
-
-	e.inputIndex = (e.inputIndex + 1) % len(e.inputDevices)
-	name := e.inputDevices[e.inputIndex].Name
-
-	e.logger.Info("switched input device", keys.DeviceName(name))
+	_ = e.switchDevicesLocked((e.inputIndex+1)%len(e.inputDevices), e.outputIndex)
 
-	if err := e.startStream(); err != nil {
-		e.logger.Error("failed to restart stream", keys.Error(err))
-	}
-
-	e.stateConfig.SetInputDevice(name)
-
-	return name
+	return e.inputDevices[e.inputIndex].Name
 }
 
 func (e *Engine) NextOutputDevice() string {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	if len(e.outputDevices) == 0 {
+	if len(e.inputDevices) == 0 || len(e.outputDevices) == 0 {
 		return ""
 	}
 
-	e.stopStream()
-
-	e.outputIndex = (e.outputIndex + 1) % len(e.outputDevices)
-	name := e.outputDevices[e.outputIndex].Name
-
-	e.logger.Info("switched output device", keys.DeviceName(name))
-
-	if err := e.startStream(); err != nil {
-		e.logger.Error("failed to restart stream", keys.Error(err))
-	}
-
-	e.stateConfig.SetOutputDevice(name)
+	_ = e.switchDevicesLocked(e.inputIndex, (e.outputIndex+1)%len(e.outputDevices))
 
-	return name
+	return e.outputDevices[e.outputIndex].Name
 }
 
 func (e *Engine) CurrentInputDevice() string {
@@ -296,10 +404,115 @@ func (e *Engine) RhythmEngine() *rhythm.Engine {
 	return e.rhythmEngine
 }
 
+func (e *Engine) Stats() Stats {
+	stats := e.monitor.stats()
+	stats.Effects = e.chain.EffectTimings()
+	if sampleRate := e.StreamFormat().SampleRate; sampleRate > 0 {
+		stats.ChainLatency = time.Duration(e.chain.LatencyFrames()) * time.Second / time.Duration(sampleRate)
+	}
+	return stats
+}
+
+func (e *Engine) InputGainDb() float64 {
+	return e.stateConfig.InputGainDb
+}
+
+func (e *Engine) OutputVolumeDb() float64 {
+	return e.stateConfig.OutputVolumeDb
+}
+
+func (e *Engine) AdjustInputGain(deltaDb float64) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	db := clampDb(e.stateConfig.InputGainDb+deltaDb, MinInputGainDb, MaxInputGainDb)
+	e.inputGain.setDb(db)
+	e.stateConfig.SetInputGainDb(db)
+
+	e.logger.Debug("input gain changed", keys.AudioGainDb(db))
+
+	return db
+}
+
+func (e *Engine) AdjustOutputVolume(deltaDb float64) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	db := clampDb(e.stateConfig.OutputVolumeDb+deltaDb, MinOutputVolumeDb, MaxOutputVolumeDb)
+	e.outputVolume.setDb(db)
+	e.stateConfig.SetOutputVolumeDb(db)
+
+	e.logger.Debug("output volume changed", keys.AudioGainDb(db))
+
+	return db
+}
+
+func clampDb(db, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, db))
+}
+
+func (e *Engine) InputLevels() Levels {
+	return e.inputMeter.levels()
+}
+
+func (e *Engine) OutputLevels() Levels {
+	return e.outputMeter.levels()
+}
+
+func (e *Engine) logStats(done <-chan struct{}) {
+	ticker := time.NewTicker(statsLogInterval)
+	defer ticker.Stop()
+
+	var lastXruns int64
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		stats := e.Stats()
+		windowPeak := e.monitor.takeWindowPeak()
+		xruns := stats.Xruns() - lastXruns
+		lastXruns = stats.Xruns()
+
+		e.logger.Info("audio performance",
+			keys.AudioLoadPercent(stats.Load),
+			keys.AudioPeakLoadPercent(windowPeak),
+			keys.AudioXruns(int(xruns)),
+		)
+
+		logEffect := e.logger.Debug
+		if xruns > 0 || windowPeak >= highLoadPercent {
+			logEffect = e.logger.Warn
+		}
+
+		for _, timing := range stats.Effects {
+			load := 0.0
+			if stats.BufferDuration > 0 {
+				load = float64(timing.Average) / float64(stats.BufferDuration) * 100
+			}
+
+			logEffect("effect timing",
+				keys.EffectName(timing.Name),
+				keys.EffectAvgUs(float64(timing.Average)/float64(time.Microsecond)),
+				keys.EffectPeakUs(float64(timing.Peak)/float64(time.Microsecond)),
+				keys.AudioLoadPercent(load),
+			)
+		}
+	}
+}
+
 func (e *Engine) Stop() error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	if e.statsDone != nil {
+		close(e.statsDone)
+		e.statsDone = nil
+	}
+
 	e.stopStream()
 
 	if e.onsetDetector != nil {
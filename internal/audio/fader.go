@@ -0,0 +1,83 @@
+package audio
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/chloyka/gorig/internal/dsp"
+)
+
+const (
+	streamFadeDuration = 10 * time.Millisecond
+
+	fadeOutTimeout = 100 * time.Millisecond
+)
+
+type fader struct {
+	step   float32
+	gain   float32
+	target atomic.Bool
+	silent atomic.Bool
+}
+
+func newFader(sampleRate int) *fader {
+	f := &fader{}
+	f.setSampleRate(sampleRate)
+	f.silent.Store(true)
+	return f
+}
+
+func (f *fader) setSampleRate(sampleRate int) {
+	frames := int(streamFadeDuration.Seconds() * float64(sampleRate))
+	if frames < 1 {
+		frames = 1
+	}
+
+	f.step = 1 / float32(frames)
+}
+
+func (f *fader) fadeIn() {
+	f.gain = 0
+	f.silent.Store(true)
+	f.target.Store(true)
+}
+
+func (f *fader) fadeOut() {
+	f.target.Store(false)
+}
+
+func (f *fader) waitSilent() {
+	deadline := time.Now().Add(fadeOutTimeout)
+	for !f.silent.Load() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (f *fader) process(buf *dsp.Buffer) {
+	target := float32(0)
+	if f.target.Load() {
+		target = 1
+	}
+
+	if f.gain == target {
+		if target == 0 {
+			buf.Clear()
+			f.silent.Store(true)
+		}
+		return
+	}
+
+	f.silent.Store(false)
+
+	channels := buf.Channels()
+	for i := 0; i < buf.Frames(); i++ {
+		if f.gain < target {
+			f.gain = min(f.gain+f.step, target)
+		} else if f.gain > target {
+			f.gain = max(f.gain-f.step, target)
+		}
+		for ch := 0; ch < channels; ch++ {
+			buf.Channel(ch)[i] *= f.gain
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package audio
+
+import (
+	"time"
+
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/logger/keys"
+	errs "github.com/chloyka/gorig/utils/errors"
+	"github.com/gordonklaus/portaudio"
+)
+
+var (
+	defaultSampleRates = []int{48000, 44100, 96000, 88200}
+
+	defaultBufferSizes = []int{64, 128, 256, 512, 1024}
+)
+
+type StreamFormat struct {
+	SampleRate      int
+	FramesPerBuffer int
+	Fallback        bool
+}
+
+func (e *Engine) StreamFormat() StreamFormat {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.format
+}
+
+func (e *Engine) StreamSettings() (sampleRate, framesPerBuffer int, targetLatency time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.cfg.SampleRate, e.cfg.FramesPerBuffer, e.cfg.TargetLatency
+}
+
+func (e *Engine) SetStreamSettings(sampleRate, framesPerBuffer int, targetLatency time.Duration) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	prevRate, prevFrames, prevLatency := e.cfg.SampleRate, e.cfg.FramesPerBuffer, e.cfg.TargetLatency
+
+	e.cfg.SampleRate = sampleRate
+	e.cfg.FramesPerBuffer = framesPerBuffer
+	e.cfg.TargetLatency = targetLatency
+
+	if err := e.restartStreamLocked(); err != nil {
+		e.cfg.SampleRate, e.cfg.FramesPerBuffer, e.cfg.TargetLatency = prevRate, prevFrames, prevLatency
+		if restoreErr := e.restartStreamLocked(); restoreErr != nil {
+			e.logger.Error("failed to restore previous stream settings", keys.Error(restoreErr))
+		}
+		return err
+	}
+
+	e.cfg.SetStreamSettings(sampleRate, framesPerBuffer, targetLatency)
+
+	e.logger.Info("stream settings changed",
+		keys.AudioSampleRate(e.format.SampleRate),
+		keys.AudioFramesPerBuffer(e.format.FramesPerBuffer),
+		keys.AudioTargetLatencyMs(targetLatency.Seconds()*1000),
+	)
+
+	return nil
+}
+
+func (e *Engine) sampleRateCandidates(params portaudio.StreamParameters) []int {
+	fallbacks := e.cfg.SampleRateFallbacks
+	if len(fallbacks) == 0 {
+		fallbacks = defaultSampleRates
+	}
+
+	candidates := append([]int{e.cfg.SampleRate}, fallbacks...)
+	if params.Input.Device != nil {
+		candidates = append(candidates, int(params.Input.Device.DefaultSampleRate))
+	}
+	if params.Output.Device != nil {
+		candidates = append(candidates, int(params.Output.Device.DefaultSampleRate))
+	}
+	return uniquePositive(candidates)
+}
+
+func (e *Engine) bufferSizeCandidates() []int {
+	fallbacks := e.cfg.BufferSizeFallbacks
+	if len(fallbacks) == 0 {
+		fallbacks = defaultBufferSizes
+	}
+	return uniquePositive(append([]int{e.cfg.FramesPerBuffer}, fallbacks...))
+}
+
+func uniquePositive(values []int) []int {
+	seen := make(map[int]bool, len(values))
+	out := make([]int, 0, len(values))
+	for _, v := range values {
+		if v <= 0 || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+func (e *Engine) openNegotiatedStream(params portaudio.StreamParameters, callback any) (*portaudio.Stream, StreamFormat, error) {
+	var lastErr error
+
+	for _, rate := range e.sampleRateCandidates(params) {
+		params.SampleRate = float64(rate)
+
+		if err := portaudio.IsFormatSupported(params, callback); err != nil {
+			e.logger.Debug("sample rate not supported", keys.AudioSampleRate(rate), keys.Error(err))
+			lastErr = err
+			continue
+		}
+
+		for _, frames := range e.bufferSizeCandidates() {
+			params.FramesPerBuffer = frames
+
+			stream, err := portaudio.OpenStream(params, callback)
+			if err != nil {
+				e.logger.Debug("stream format rejected",
+					keys.AudioSampleRate(rate),
+					keys.AudioFramesPerBuffer(frames),
+					keys.Error(err),
+				)
+				lastErr = err
+				continue
+			}
+
+			format := StreamFormat{
+				SampleRate:      rate,
+				FramesPerBuffer: frames,
+				Fallback:        rate != e.cfg.SampleRate || frames != e.cfg.FramesPerBuffer,
+			}
+			return stream, format, nil
+		}
+	}
+
+	if lastErr == nil {
+		return nil, StreamFormat{}, errs.ErrAudioFormat
+	}
+	return nil, StreamFormat{}, errs.Wrap(errs.ErrAudioFormat, lastErr)
+}
+
+func (e *Engine) applyStreamFormat(format StreamFormat) {
+	e.format = format
+
+	e.processBuf.Resize(dsp.MaxChannels, format.FramesPerBuffer)
+	if len(e.onsetBuf) < format.FramesPerBuffer {
+		e.onsetBuf = make([]float32, format.FramesPerBuffer)
+	}
+
+	e.fader.setSampleRate(format.SampleRate)
+	e.inputMeter.setSampleRate(format.SampleRate)
+	e.outputMeter.setSampleRate(format.SampleRate)
+	e.inputGain.setSampleRate(format.SampleRate)
+	e.outputVolume.setSampleRate(format.SampleRate)
+
+	e.chain.SetStreamFormat(format.SampleRate, format.FramesPerBuffer)
+
+	if e.onsetDetector != nil {
+		e.onsetDetector.SetSampleRate(float32(format.SampleRate))
+	}
+	if e.rhythmEngine != nil {
+		e.rhythmEngine.SetSampleRate(float32(format.SampleRate))
+	}
+}
@@ -0,0 +1,128 @@
+package audio
+
+import (
+	"slices"
+	"testing"
+
+	configTypes "github.com/chloyka/gorig/internal/config/types"
+	"github.com/gordonklaus/portaudio"
+)
+
+func TestFormat(t *testing.T) {
+	t.Run("sampleRateCandidates", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			cfg       configTypes.AudioConfig
+			inputRate float64
+			output    float64
+			want      []int
+		}{
+			{
+				name: "should try configured rate before default fallbacks",
+				cfg:  configTypes.AudioConfig{SampleRate: 96000},
+				want: []int{96000, 48000, 44100, 88200},
+			},
+			{
+				name: "should use configured fallbacks instead of defaults",
+				cfg:  configTypes.AudioConfig{SampleRate: 48000, SampleRateFallbacks: []int{44100}},
+				want: []int{48000, 44100},
+			},
+			{
+				name:      "should append device default rates last",
+				cfg:       configTypes.AudioConfig{SampleRate: 48000, SampleRateFallbacks: []int{44100}},
+				inputRate: 32000,
+				output:    22050,
+				want:      []int{48000, 44100, 32000, 22050},
+			},
+			{
+				name:      "should drop duplicate device rates",
+				cfg:       configTypes.AudioConfig{SampleRate: 48000, SampleRateFallbacks: []int{44100}},
+				inputRate: 44100,
+				output:    48000,
+				want:      []int{48000, 44100},
+			},
+			{
+				name: "should skip unset configured rate",
+				cfg:  configTypes.AudioConfig{SampleRateFallbacks: []int{44100}},
+				want: []int{44100},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := &Engine{cfg: &tt.cfg}
+				params := portaudio.StreamParameters{}
+				if tt.inputRate > 0 {
+					params.Input.Device = &portaudio.DeviceInfo{DefaultSampleRate: tt.inputRate}
+				}
+				if tt.output > 0 {
+					params.Output.Device = &portaudio.DeviceInfo{DefaultSampleRate: tt.output}
+				}
+
+				got := sut.sampleRateCandidates(params)
+
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("bufferSizeCandidates", func(t *testing.T) {
+		tests := []struct {
+			name string
+			cfg  configTypes.AudioConfig
+			want []int
+		}{
+			{
+				name: "should try configured size before default fallbacks",
+				cfg:  configTypes.AudioConfig{FramesPerBuffer: 32},
+				want: []int{32, 64, 128, 256, 512, 1024},
+			},
+			{
+				name: "should not repeat configured size found in defaults",
+				cfg:  configTypes.AudioConfig{FramesPerBuffer: 256},
+				want: []int{256, 64, 128, 512, 1024},
+			},
+			{
+				name: "should use configured fallbacks instead of defaults",
+				cfg:  configTypes.AudioConfig{FramesPerBuffer: 128, BufferSizeFallbacks: []int{256, 128, 512}},
+				want: []int{128, 256, 512},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := &Engine{cfg: &tt.cfg}
+
+				got := sut.bufferSizeCandidates()
+
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("uniquePositive", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			values []int
+			want   []int
+		}{
+			{name: "should keep first occurrence order", values: []int{3, 1, 3, 2, 1}, want: []int{3, 1, 2}},
+			{name: "should drop zero and negative values", values: []int{0, -1, 5, 0}, want: []int{5}},
+			{name: "should return empty slice for empty input", values: nil, want: []int{}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got := uniquePositive(tt.values)
+
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+}
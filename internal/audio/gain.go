@@ -0,0 +1,84 @@
+package audio
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/chloyka/gorig/internal/dsp"
+)
+
+const (
+	GainStepDb = 1.0
+
+	MinInputGainDb = -24.0
+	MaxInputGainDb = 24.0
+
+	MinOutputVolumeDb = -60.0
+	MaxOutputVolumeDb = 6.0
+
+	gainSmoothingTime = 20 * time.Millisecond
+
+	gainSnapThreshold = 1e-5
+)
+
+type gainStage struct {
+	target  atomic.Uint32
+	current float64
+	coef    float64
+}
+
+func newGainStage(sampleRate int, db float64) *gainStage {
+	g := &gainStage{}
+	g.setSampleRate(sampleRate)
+	g.setDb(db)
+	g.current = float64(g.targetGain())
+	return g
+}
+
+func (g *gainStage) setSampleRate(sampleRate int) {
+	frames := gainSmoothingTime.Seconds() * float64(sampleRate)
+	g.coef = math.Exp(-1 / max(frames, 1))
+}
+
+func dbToGain(db float64) float32 {
+	return float32(math.Pow(10, db/20))
+}
+
+func (g *gainStage) setDb(db float64) {
+	g.target.Store(math.Float32bits(dbToGain(db)))
+}
+
+func (g *gainStage) targetGain() float32 {
+	return math.Float32frombits(g.target.Load())
+}
+
+func (g *gainStage) process(buf *dsp.Buffer) {
+	target := float64(g.targetGain())
+	channels := buf.Channels()
+
+	if g.current == target {
+		if target != 1 {
+			gain := float32(target)
+			for ch := 0; ch < channels; ch++ {
+				samples := buf.Channel(ch)
+				for i := range samples {
+					samples[i] *= gain
+				}
+			}
+		}
+		return
+	}
+
+	for i := 0; i < buf.Frames(); i++ {
+		g.current = target + (g.current-target)*g.coef
+		gain := float32(g.current)
+		for ch := 0; ch < channels; ch++ {
+			buf.Channel(ch)[i] *= gain
+		}
+	}
+
+	if diff := g.current - target; diff < gainSnapThreshold && diff > -gainSnapThreshold {
+		g.current = target
+	}
+}
@@ -0,0 +1,87 @@
+package audio
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chloyka/gorig/internal/dsp"
+)
+
+const gainTestRate = 48000
+
+func runGainStage(sut *gainStage, frames int) []float32 {
+	buf := dsp.NewBuffer(1, frames)
+	samples := buf.Channel(0)
+	for i := range samples {
+		samples[i] = 1
+	}
+	sut.process(buf)
+	return samples
+}
+
+func TestGainStage(t *testing.T) {
+	t.Run("process", func(t *testing.T) {
+		t.Run("should ramp a dB step over the smoothing time", func(t *testing.T) {
+			sut := newGainStage(gainTestRate, 0)
+			sut.setDb(6)
+			target := float64(dbToGain(6))
+
+			got := runGainStage(sut, gainTestRate/10)
+
+			at := int(gainSmoothingTime.Seconds() * gainTestRate)
+			want := target - (target-1)*math.Exp(-1)
+			if math.Abs(float64(got[at-1])-want) > 1e-3 {
+				t.Errorf("got %v after one time constant, want %v", got[at-1], want)
+			}
+			if got[0] >= float32(target) || got[0] <= 1 {
+				t.Errorf("got %v on the first frame, want between 1 and %v", got[0], target)
+			}
+		})
+
+		t.Run("should change gain without a discontinuity", func(t *testing.T) {
+			sut := newGainStage(gainTestRate, 0)
+			sut.setDb(MaxInputGainDb)
+
+			got := runGainStage(sut, gainTestRate/10)
+
+			limit := float64(dbToGain(MaxInputGainDb)-1) / (gainSmoothingTime.Seconds() * gainTestRate)
+			prev := float32(1)
+			for i, s := range got {
+				if step := math.Abs(float64(s - prev)); step > limit*1.01 {
+					t.Fatalf("got step %v at frame %d, want at most %v", step, i, limit)
+				}
+				prev = s
+			}
+		})
+
+		t.Run("should snap exactly to the target once settled", func(t *testing.T) {
+			sut := newGainStage(gainTestRate, 0)
+			sut.setDb(-12)
+			target := dbToGain(-12)
+
+			runGainStage(sut, gainTestRate)
+			got := runGainStage(sut, 64)
+
+			if sut.current != float64(target) {
+				t.Errorf("got current gain %v, want exactly %v", sut.current, target)
+			}
+			for i, s := range got {
+				if s != target {
+					t.Fatalf("got %v at frame %d, want steady %v", s, i, target)
+				}
+			}
+		})
+
+		t.Run("should leave samples untouched at unity", func(t *testing.T) {
+			sut := newGainStage(gainTestRate, 0)
+
+			got := runGainStage(sut, 64)
+
+			for i, s := range got {
+				if s != 1 {
+					t.Fatalf("got %v at frame %d, want 1", s, i)
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,259 @@
+package audio
+
+import (
+	"math"
+	"math/cmplx"
+	"sync/atomic"
+	"time"
+
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/logger/keys"
+	errs "github.com/chloyka/gorig/utils/errors"
+)
+
+const (
+	mlsOrder = 12
+
+	mlsTaps = 0x829
+
+	mlsAmplitude = 0.25
+
+	latencyMaxRoundTrip = time.Second
+
+	latencyRunGap = 200 * time.Millisecond
+
+	latencyMinCorrelation = 0.2
+
+	latencySilenceEnergy = 1e-6
+)
+
+const (
+	probeIdle int32 = iota
+	probeRunning
+	probeDone
+)
+
+type LatencyRun struct {
+	Samples     int
+	Latency     time.Duration
+	Correlation float64
+}
+
+type LatencyReport struct {
+	SampleRate int
+	Runs       []LatencyRun
+	Mean       time.Duration
+	Min        time.Duration
+	Max        time.Duration
+	Jitter     time.Duration
+	Reported   time.Duration
+}
+
+type latencyProbe struct {
+	state   atomic.Int32
+	busy    atomic.Bool
+	signal  []float32
+	capture []float32
+	pos     int
+	fft     *dsp.FFT
+}
+
+func newMLS(order int, taps uint32, amplitude float32) []float32 {
+	length := 1<<order - 1
+	out := make([]float32, length)
+
+	reg := uint32(1)
+	for i := range out {
+		bit := reg & 1
+		reg >>= 1
+		if bit == 1 {
+			reg ^= taps
+			out[i] = amplitude
+		} else {
+			out[i] = -amplitude
+		}
+	}
+	return out
+}
+
+func (p *latencyProbe) running() bool {
+	return p.state.Load() == probeRunning
+}
+
+func (p *latencyProbe) arm(captureFrames int) {
+	if len(p.signal) == 0 {
+		p.signal = newMLS(mlsOrder, mlsTaps, mlsAmplitude)
+	}
+	if len(p.capture) != captureFrames {
+		p.capture = make([]float32, captureFrames)
+	}
+	p.pos = 0
+	p.state.Store(probeRunning)
+}
+
+func (p *latencyProbe) process(buf *dsp.Buffer) {
+	frames := buf.Frames()
+
+	copy(p.capture[p.pos:], buf.Channel(0))
+
+	for ch := 0; ch < buf.Channels(); ch++ {
+		samples := buf.Channel(ch)
+		for i := range samples {
+			idx := p.pos + i
+			if idx < len(p.signal) {
+				samples[i] = p.signal[idx]
+			} else {
+				samples[i] = 0
+			}
+		}
+	}
+
+	p.pos += frames
+	if p.pos >= len(p.capture) {
+		p.state.Store(probeDone)
+	}
+}
+
+func (p *latencyProbe) correlate() (lag int, correlation float64) {
+	lags := len(p.capture) - len(p.signal) + 1
+	if lags <= 0 {
+		return 0, 0
+	}
+
+	size := len(p.capture) + len(p.signal)
+	if p.fft == nil || p.fft.Size() < size {
+		p.fft = dsp.NewFFT(size)
+	}
+	n := p.fft.Size()
+
+	captured := make([]complex128, n)
+	for i, x := range p.capture {
+		captured[i] = complex(float64(x), 0)
+	}
+	reference := make([]complex128, n)
+	signalEnergy := 0.0
+	for i, s := range p.signal {
+		reference[i] = complex(float64(s), 0)
+		signalEnergy += float64(s) * float64(s)
+	}
+
+	p.fft.Forward(captured)
+	p.fft.Forward(reference)
+	for i := range captured {
+		captured[i] *= cmplx.Conj(reference[i])
+	}
+	p.fft.Inverse(captured)
+
+	energies := make([]float64, len(p.capture)+1)
+	for i, x := range p.capture {
+		energies[i+1] = energies[i] + float64(x)*float64(x)
+	}
+
+	floor := signalEnergy * latencySilenceEnergy
+	best := 0.0
+	for l := range lags {
+		energy := energies[l+len(p.signal)] - energies[l]
+		if energy <= floor {
+			continue
+		}
+
+		r := math.Abs(real(captured[l])) / math.Sqrt(signalEnergy*energy)
+		if r > best {
+			best = r
+			lag = l
+		}
+	}
+	return lag, best
+}
+
+func (e *Engine) MeasureLatency(runs int) (LatencyReport, error) {
+	if !e.probe.busy.CompareAndSwap(false, true) {
+		return LatencyReport{}, errs.ErrAudioLatencyBusy
+	}
+	defer e.probe.busy.Store(false)
+
+	e.mu.Lock()
+	running := e.stream != nil
+	sampleRate := e.format.SampleRate
+	e.mu.Unlock()
+
+	if !running || sampleRate == 0 {
+		return LatencyReport{}, errs.ErrAudioStreamStopped
+	}
+
+	signalLen := 1<<mlsOrder - 1
+	captureFrames := signalLen + int(latencyMaxRoundTrip.Seconds()*float64(sampleRate))
+	timeout := time.Duration(captureFrames)*time.Second/time.Duration(sampleRate) + time.Second
+
+	report := LatencyReport{SampleRate: sampleRate}
+
+	for run := 0; run < runs; run++ {
+		e.probe.arm(captureFrames)
+
+		deadline := time.Now().Add(timeout)
+		for e.probe.state.Load() != probeDone {
+			if time.Now().After(deadline) {
+				e.probe.state.Store(probeIdle)
+				return report, errs.ErrAudioLatencyTimeout
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		lag, correlation := e.probe.correlate()
+		e.probe.state.Store(probeIdle)
+
+		if correlation < latencyMinCorrelation {
+			return report, errs.Wrap(errs.ErrAudioLatencyNoSignal, correlation)
+		}
+
+		latency := time.Duration(lag) * time.Second / time.Duration(sampleRate)
+		report.Runs = append(report.Runs, LatencyRun{Samples: lag, Latency: latency, Correlation: correlation})
+
+		e.logger.Debug("latency run",
+			keys.AudioLatencySamples(lag),
+			keys.AudioLatencyMs(latency.Seconds()*1000),
+		)
+
+		time.Sleep(latencyRunGap)
+	}
+
+	report.summarize()
+
+	stats := e.Stats()
+	report.Reported = stats.InputLatency + stats.OutputLatency
+
+	e.logger.Info("round-trip latency measured",
+		keys.AudioLatencyMs(report.Mean.Seconds()*1000),
+		keys.AudioJitterMs(report.Jitter.Seconds()*1000),
+		keys.AudioSampleRate(sampleRate),
+	)
+
+	return report, nil
+}
+
+func (r *LatencyReport) summarize() {
+	if len(r.Runs) == 0 {
+		return
+	}
+
+	r.Min = r.Runs[0].Latency
+	r.Max = r.Runs[0].Latency
+
+	var sum float64
+	for _, run := range r.Runs {
+		sum += float64(run.Latency)
+		r.Min = min(r.Min, run.Latency)
+		r.Max = max(r.Max, run.Latency)
+	}
+	mean := sum / float64(len(r.Runs))
+
+	var variance float64
+	for _, run := range r.Runs {
+		d := float64(run.Latency) - mean
+		variance += d * d
+	}
+	variance /= float64(len(r.Runs))
+
+	r.Mean = time.Duration(mean)
+	r.Jitter = time.Duration(math.Sqrt(variance))
+}
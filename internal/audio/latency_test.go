@@ -0,0 +1,159 @@
+package audio
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestLatency(t *testing.T) {
+	t.Run("newMLS", func(t *testing.T) {
+		t.Run("should produce a balanced two-level sequence", func(t *testing.T) {
+			got := newMLS(mlsOrder, mlsTaps, mlsAmplitude)
+
+			if len(got) != 1<<mlsOrder-1 {
+				t.Fatalf("got length %d, want %d", len(got), 1<<mlsOrder-1)
+			}
+			positive := 0
+			for _, s := range got {
+				switch s {
+				case mlsAmplitude:
+					positive++
+				case -mlsAmplitude:
+				default:
+					t.Fatalf("got sample %v, want +/-%v", s, mlsAmplitude)
+				}
+			}
+			if want := 1 << (mlsOrder - 1); positive != want {
+				t.Errorf("got %d positive samples, want %d", positive, want)
+			}
+		})
+	})
+
+	t.Run("correlate", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			delay int
+			gain  float32
+		}{
+			{name: "should find a delayed loopback", delay: 300, gain: 0.5},
+			{name: "should find an immediate loopback", delay: 0, gain: 1},
+			{name: "should find a polarity-inverted loopback", delay: 1234, gain: -0.25},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := &latencyProbe{signal: newMLS(mlsOrder, mlsTaps, mlsAmplitude)}
+				sut.capture = make([]float32, len(sut.signal)+2000)
+				for i, s := range sut.signal {
+					sut.capture[tt.delay+i] = s * tt.gain
+				}
+
+				lag, correlation := sut.correlate()
+
+				if lag != tt.delay {
+					t.Errorf("got lag %d, want %d", lag, tt.delay)
+				}
+				if correlation < 0.99 {
+					t.Errorf("got correlation %v, want ~1", correlation)
+				}
+			})
+		}
+
+		t.Run("should match a direct correlation on a noisy capture", func(t *testing.T) {
+			sut := &latencyProbe{signal: newMLS(mlsOrder, mlsTaps, mlsAmplitude)}
+			sut.capture = make([]float32, len(sut.signal)+3000)
+			rng := rand.New(rand.NewSource(1))
+			for i := range sut.capture {
+				sut.capture[i] = float32(rng.NormFloat64() * 0.05)
+			}
+			for i, s := range sut.signal {
+				sut.capture[1777+i] += s * 0.3
+			}
+			wantLag, want := directCorrelation(sut.signal, sut.capture)
+
+			lag, got := sut.correlate()
+
+			if lag != wantLag || math.Abs(got-want) > 1e-9 {
+				t.Errorf("got lag %d correlation %v, want %d and %v", lag, got, wantLag, want)
+			}
+		})
+
+		t.Run("should find the lag across a full round-trip window", func(t *testing.T) {
+			sut := &latencyProbe{signal: newMLS(mlsOrder, mlsTaps, mlsAmplitude)}
+			sut.capture = make([]float32, len(sut.signal)+int(latencyMaxRoundTrip.Seconds()*48000))
+			for i, s := range sut.signal {
+				sut.capture[45000+i] = s * 0.1
+			}
+
+			lag, correlation := sut.correlate()
+
+			if lag != 45000 || correlation < 0.99 {
+				t.Errorf("got lag %d correlation %v, want 45000 and ~1", lag, correlation)
+			}
+		})
+
+		t.Run("should report weak correlation for silence", func(t *testing.T) {
+			sut := &latencyProbe{signal: newMLS(mlsOrder, mlsTaps, mlsAmplitude)}
+			sut.capture = make([]float32, len(sut.signal)+100)
+
+			_, got := sut.correlate()
+
+			if got >= latencyMinCorrelation {
+				t.Errorf("got correlation %v, want below %v", got, latencyMinCorrelation)
+			}
+		})
+	})
+
+	t.Run("summarize", func(t *testing.T) {
+		t.Run("should compute mean, range and jitter", func(t *testing.T) {
+			sut := LatencyReport{Runs: []LatencyRun{
+				{Latency: 1 * time.Millisecond},
+				{Latency: 3 * time.Millisecond},
+				{Latency: 2 * time.Millisecond},
+			}}
+
+			sut.summarize()
+
+			if sut.Mean != 2*time.Millisecond || sut.Min != time.Millisecond || sut.Max != 3*time.Millisecond {
+				t.Errorf("got mean=%v min=%v max=%v, want 2ms 1ms 3ms", sut.Mean, sut.Min, sut.Max)
+			}
+			want := time.Duration(math.Sqrt(2.0/3) * float64(time.Millisecond))
+			if diff := sut.Jitter - want; diff < -time.Microsecond || diff > time.Microsecond {
+				t.Errorf("got jitter %v, want %v", sut.Jitter, want)
+			}
+		})
+
+		t.Run("should leave an empty report untouched", func(t *testing.T) {
+			sut := LatencyReport{}
+
+			sut.summarize()
+
+			if sut.Mean != 0 || sut.Jitter != 0 {
+				t.Errorf("got mean=%v jitter=%v, want zero", sut.Mean, sut.Jitter)
+			}
+		})
+	})
+}
+
+func directCorrelation(signal, capture []float32) (int, float64) {
+	var signalEnergy float64
+	for _, s := range signal {
+		signalEnergy += float64(s) * float64(s)
+	}
+
+	lag, best := 0, 0.0
+	for l := 0; l+len(signal) <= len(capture); l++ {
+		var dot, energy float64
+		for i, s := range signal {
+			x := float64(capture[l+i])
+			dot += float64(s) * x
+			energy += x * x
+		}
+		if r := math.Abs(dot) / math.Sqrt(signalEnergy*energy); r > best {
+			lag, best = l, r
+		}
+	}
+	return lag, best
+}
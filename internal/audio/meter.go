@@ -0,0 +1,110 @@
+package audio
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/chloyka/gorig/internal/dsp"
+)
+
+const (
+	meterPeakHold = 1500 * time.Millisecond
+
+	meterClipHold = 2 * time.Second
+
+	meterRMSWindow = 300 * time.Millisecond
+
+	meterPeakFalloffDb = 24.0
+
+	clipThreshold = 0.999
+)
+
+type Levels struct {
+	Peak float32
+	RMS  float32
+	Hold float32
+	Clip bool
+}
+
+type meter struct {
+	sampleRate float64
+
+	peak       float32
+	meanSquare float64
+	hold       float32
+	holdAge    time.Duration
+	clipAge    time.Duration
+	clipping   bool
+
+	peakBits atomic.Uint32
+	rmsBits  atomic.Uint32
+	holdBits atomic.Uint32
+	clip     atomic.Bool
+}
+
+func newMeter(sampleRate int) *meter {
+	return &meter{sampleRate: float64(sampleRate)}
+}
+
+func (m *meter) setSampleRate(sampleRate int) {
+	m.sampleRate = float64(sampleRate)
+}
+
+func (m *meter) process(buf *dsp.Buffer) {
+	if buf.Frames() == 0 || m.sampleRate <= 0 {
+		return
+	}
+
+	var bufPeak float32
+	var sum float64
+	for ch := 0; ch < buf.Channels(); ch++ {
+		for _, s := range buf.Channel(ch) {
+			if s < 0 {
+				s = -s
+			}
+			if s > bufPeak {
+				bufPeak = s
+			}
+			sum += float64(s) * float64(s)
+		}
+	}
+
+	count := buf.Frames() * buf.Channels()
+	seconds := float64(buf.Frames()) / m.sampleRate
+	elapsed := time.Duration(seconds * float64(time.Second))
+
+	falloff := float32(math.Pow(10, -meterPeakFalloffDb*seconds/20))
+	m.peak = max(bufPeak, m.peak*falloff)
+
+	alpha := 1 - math.Exp(-seconds/meterRMSWindow.Seconds())
+	m.meanSquare += alpha * (sum/float64(count) - m.meanSquare)
+
+	m.holdAge += elapsed
+	if bufPeak >= m.hold || m.holdAge >= meterPeakHold {
+		m.hold = bufPeak
+		m.holdAge = 0
+	}
+
+	m.clipAge += elapsed
+	if bufPeak >= clipThreshold {
+		m.clipping = true
+		m.clipAge = 0
+	} else if m.clipAge >= meterClipHold {
+		m.clipping = false
+	}
+
+	m.peakBits.Store(math.Float32bits(m.peak))
+	m.rmsBits.Store(math.Float32bits(float32(math.Sqrt(m.meanSquare))))
+	m.holdBits.Store(math.Float32bits(m.hold))
+	m.clip.Store(m.clipping)
+}
+
+func (m *meter) levels() Levels {
+	return Levels{
+		Peak: math.Float32frombits(m.peakBits.Load()),
+		RMS:  math.Float32frombits(m.rmsBits.Load()),
+		Hold: math.Float32frombits(m.holdBits.Load()),
+		Clip: m.clip.Load(),
+	}
+}
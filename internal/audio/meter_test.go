@@ -0,0 +1,101 @@
+package audio
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/chloyka/gorig/internal/dsp"
+)
+
+const meterTestRate = 48000
+
+func meterBlock(value float32) *dsp.Buffer {
+	buf := dsp.NewBuffer(2, meterTestRate/100)
+	for ch := 0; ch < buf.Channels(); ch++ {
+		for i := range buf.Channel(ch) {
+			buf.Channel(ch)[i] = value
+		}
+	}
+	return buf
+}
+
+func feedMeter(sut *meter, value float32, d time.Duration) Levels {
+	buf := meterBlock(value)
+	for range d / (10 * time.Millisecond) {
+		sut.process(buf)
+	}
+	return sut.levels()
+}
+
+func TestMeter(t *testing.T) {
+	t.Run("process", func(t *testing.T) {
+		t.Run("should hold the peak until the hold time expires", func(t *testing.T) {
+			sut := newMeter(meterTestRate)
+			feedMeter(sut, 0.8, 10*time.Millisecond)
+
+			held := feedMeter(sut, 0.1, meterPeakHold-100*time.Millisecond)
+			got := feedMeter(sut, 0.1, 200*time.Millisecond)
+
+			if held.Hold != 0.8 {
+				t.Errorf("got hold %v before expiry, want 0.8", held.Hold)
+			}
+			if got.Hold != 0.1 {
+				t.Errorf("got hold %v after expiry, want 0.1", got.Hold)
+			}
+		})
+
+		t.Run("should latch clip and release it after the clip hold", func(t *testing.T) {
+			sut := newMeter(meterTestRate)
+			clipped := feedMeter(sut, 1, 10*time.Millisecond)
+
+			held := feedMeter(sut, 0.5, meterClipHold-100*time.Millisecond)
+			got := feedMeter(sut, 0.5, 200*time.Millisecond)
+
+			if !clipped.Clip || !held.Clip {
+				t.Errorf("got clip %v then %v, want latched", clipped.Clip, held.Clip)
+			}
+			if got.Clip {
+				t.Error("got clip still latched, want released after the hold")
+			}
+		})
+
+		t.Run("should converge RMS on a constant signal", func(t *testing.T) {
+			sut := newMeter(meterTestRate)
+
+			early := feedMeter(sut, 0.5, meterRMSWindow)
+			got := feedMeter(sut, 0.5, 10*meterRMSWindow)
+
+			if want := 0.5 * math.Sqrt(1-math.Exp(-1)); math.Abs(float64(early.RMS)-want) > 0.01 {
+				t.Errorf("got RMS %v after one window, want %v", early.RMS, want)
+			}
+			if math.Abs(float64(got.RMS)-0.5) > 1e-3 {
+				t.Errorf("got RMS %v, want 0.5", got.RMS)
+			}
+		})
+
+		t.Run("should let the peak fall by the falloff rate", func(t *testing.T) {
+			sut := newMeter(meterTestRate)
+			feedMeter(sut, 1, 10*time.Millisecond)
+
+			got := feedMeter(sut, 0, time.Second)
+
+			if db := dsp.LinearToDb(float64(got.Peak)); math.Abs(db+meterPeakFalloffDb) > 0.5 {
+				t.Errorf("got %.1f dB after 1 s, want -%v", db, meterPeakFalloffDb)
+			}
+		})
+
+		t.Run("should not allocate", func(t *testing.T) {
+			sut := newMeter(meterTestRate)
+			buf := meterBlock(0.5)
+
+			got := testing.AllocsPerRun(100, func() {
+				sut.process(buf)
+			})
+
+			if got != 0 {
+				t.Errorf("got %v allocs per run, want 0", got)
+			}
+		})
+	})
+}
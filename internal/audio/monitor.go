@@ -0,0 +1,125 @@
+package audio
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/chloyka/gorig/internal/effects"
+	"github.com/gordonklaus/portaudio"
+)
+
+const (
+	statsLogInterval = 10 * time.Second
+
+	loadSmoothing = 0.1
+
+	highLoadPercent = 80
+)
+
+type Stats struct {
+	Load      float64
+	PeakLoad  float64
+	Callbacks int64
+
+	InputUnderflows  int64
+	InputOverflows   int64
+	OutputUnderflows int64
+	OutputOverflows  int64
+
+	BufferDuration time.Duration
+	InputLatency   time.Duration
+	OutputLatency  time.Duration
+	ChainLatency   time.Duration
+
+	Effects []effects.EffectTiming
+}
+
+func (s Stats) Xruns() int64 {
+	return s.InputUnderflows + s.InputOverflows + s.OutputUnderflows + s.OutputOverflows
+}
+
+type monitor struct {
+	load       atomic.Uint64
+	peakLoad   atomic.Uint64
+	windowPeak atomic.Uint64
+	callbacks  atomic.Int64
+
+	inputUnderflows  atomic.Int64
+	inputOverflows   atomic.Int64
+	outputUnderflows atomic.Int64
+	outputOverflows  atomic.Int64
+
+	bufferNs      atomic.Int64
+	inputLatency  atomic.Int64
+	outputLatency atomic.Int64
+}
+
+func (m *monitor) reset(info *portaudio.StreamInfo) {
+	m.load.Store(0)
+	m.peakLoad.Store(0)
+	m.windowPeak.Store(0)
+	m.bufferNs.Store(0)
+
+	if info != nil {
+		m.inputLatency.Store(int64(info.InputLatency))
+		m.outputLatency.Store(int64(info.OutputLatency))
+	}
+}
+
+func (m *monitor) record(elapsed, buffer time.Duration, flags portaudio.StreamCallbackFlags) {
+	m.callbacks.Add(1)
+	m.bufferNs.Store(int64(buffer))
+
+	if flags&portaudio.InputUnderflow != 0 {
+		m.inputUnderflows.Add(1)
+	}
+	if flags&portaudio.InputOverflow != 0 {
+		m.inputOverflows.Add(1)
+	}
+	if flags&portaudio.OutputUnderflow != 0 {
+		m.outputUnderflows.Add(1)
+	}
+	if flags&portaudio.OutputOverflow != 0 {
+		m.outputOverflows.Add(1)
+	}
+
+	if buffer <= 0 {
+		return
+	}
+
+	load := float64(elapsed) / float64(buffer) * 100
+
+	avg := math.Float64frombits(m.load.Load())
+	m.load.Store(math.Float64bits(avg + (load-avg)*loadSmoothing))
+
+	if load > math.Float64frombits(m.peakLoad.Load()) {
+		m.peakLoad.Store(math.Float64bits(load))
+	}
+
+	for {
+		old := m.windowPeak.Load()
+		if load <= math.Float64frombits(old) || m.windowPeak.CompareAndSwap(old, math.Float64bits(load)) {
+			break
+		}
+	}
+}
+
+func (m *monitor) takeWindowPeak() float64 {
+	return math.Float64frombits(m.windowPeak.Swap(0))
+}
+
+func (m *monitor) stats() Stats {
+	return Stats{
+		Load:             math.Float64frombits(m.load.Load()),
+		PeakLoad:         math.Float64frombits(m.peakLoad.Load()),
+		Callbacks:        m.callbacks.Load(),
+		InputUnderflows:  m.inputUnderflows.Load(),
+		InputOverflows:   m.inputOverflows.Load(),
+		OutputUnderflows: m.outputUnderflows.Load(),
+		OutputOverflows:  m.outputOverflows.Load(),
+		BufferDuration:   time.Duration(m.bufferNs.Load()),
+		InputLatency:     time.Duration(m.inputLatency.Load()),
+		OutputLatency:    time.Duration(m.outputLatency.Load()),
+	}
+}
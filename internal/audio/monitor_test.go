@@ -0,0 +1,128 @@
+package audio
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+func TestMonitor(t *testing.T) {
+	t.Run("record", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			flags portaudio.StreamCallbackFlags
+			want  Stats
+		}{
+			{name: "should count input underflows", flags: portaudio.InputUnderflow, want: Stats{InputUnderflows: 1}},
+			{name: "should count input overflows", flags: portaudio.InputOverflow, want: Stats{InputOverflows: 1}},
+			{name: "should count output underflows", flags: portaudio.OutputUnderflow, want: Stats{OutputUnderflows: 1}},
+			{name: "should count output overflows", flags: portaudio.OutputOverflow, want: Stats{OutputOverflows: 1}},
+			{name: "should not count priming output as an xrun", flags: portaudio.PrimingOutput},
+			{
+				name:  "should count every flag raised in one callback",
+				flags: portaudio.InputOverflow | portaudio.OutputUnderflow,
+				want:  Stats{InputOverflows: 1, OutputUnderflows: 1},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var sut monitor
+
+				sut.record(time.Millisecond, 10*time.Millisecond, tt.flags)
+
+				got := sut.stats()
+				if got.InputUnderflows != tt.want.InputUnderflows || got.InputOverflows != tt.want.InputOverflows ||
+					got.OutputUnderflows != tt.want.OutputUnderflows || got.OutputOverflows != tt.want.OutputOverflows {
+					t.Errorf("got %+v, want %+v", got, tt.want)
+				}
+				if got.Callbacks != 1 {
+					t.Errorf("got %d callbacks, want 1", got.Callbacks)
+				}
+			})
+		}
+
+		t.Run("should smooth load toward the callback's share of the buffer", func(t *testing.T) {
+			var sut monitor
+
+			sut.record(5*time.Millisecond, 10*time.Millisecond, 0)
+
+			if got := sut.stats().Load; math.Abs(got-50*loadSmoothing) > 1e-9 {
+				t.Errorf("got load %v, want %v", got, 50*loadSmoothing)
+			}
+		})
+
+		t.Run("should converge load on a steady callback", func(t *testing.T) {
+			var sut monitor
+
+			for range 200 {
+				sut.record(5*time.Millisecond, 10*time.Millisecond, 0)
+			}
+
+			if got := sut.stats().Load; math.Abs(got-50) > 0.01 {
+				t.Errorf("got load %v, want 50", got)
+			}
+		})
+
+		t.Run("should keep the highest load as peak", func(t *testing.T) {
+			var sut monitor
+
+			sut.record(9*time.Millisecond, 10*time.Millisecond, 0)
+			sut.record(time.Millisecond, 10*time.Millisecond, 0)
+
+			if got := sut.stats().PeakLoad; got != 90 {
+				t.Errorf("got peak %v, want 90", got)
+			}
+		})
+
+		t.Run("should skip load without a buffer duration", func(t *testing.T) {
+			var sut monitor
+
+			sut.record(time.Millisecond, 0, 0)
+
+			got := sut.stats()
+			if got.Load != 0 || got.PeakLoad != 0 || got.Callbacks != 1 {
+				t.Errorf("got %+v, want one callback without load", got)
+			}
+		})
+	})
+
+	t.Run("takeWindowPeak", func(t *testing.T) {
+		t.Run("should return the window peak and start a new window", func(t *testing.T) {
+			var sut monitor
+			sut.record(9*time.Millisecond, 10*time.Millisecond, 0)
+			sut.record(time.Millisecond, 10*time.Millisecond, 0)
+
+			got := sut.takeWindowPeak()
+
+			if got != 90 {
+				t.Errorf("got %v, want 90", got)
+			}
+			if next := sut.takeWindowPeak(); next != 0 {
+				t.Errorf("got %v in the next window, want 0", next)
+			}
+			if peak := sut.stats().PeakLoad; peak != 90 {
+				t.Errorf("got overall peak %v, want 90 kept", peak)
+			}
+		})
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		t.Run("should clear load but keep xrun counters", func(t *testing.T) {
+			var sut monitor
+			sut.record(9*time.Millisecond, 10*time.Millisecond, portaudio.OutputUnderflow)
+
+			sut.reset(nil)
+
+			got := sut.stats()
+			if got.Load != 0 || got.PeakLoad != 0 || got.BufferDuration != 0 {
+				t.Errorf("got %+v, want load cleared", got)
+			}
+			if got.Xruns() != 1 {
+				t.Errorf("got %d xruns, want 1 kept", got.Xruns())
+			}
+		})
+	})
+}
@@ -0,0 +1,143 @@
+package audio
+
+import (
+	configTypes "github.com/chloyka/gorig/internal/config/types"
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/logger/keys"
+	"github.com/gordonklaus/portaudio"
+)
+
+func defaultOutputRouting(channels int) configTypes.OutputRouting {
+	routing := configTypes.OutputRouting{}
+	for ch := 0; ch < max(channels, 1); ch++ {
+		routing.Channels = append(routing.Channels, ch)
+	}
+	return routing
+}
+
+func (e *Engine) resolveInputRouting(dev *portaudio.DeviceInfo) configTypes.InputRouting {
+	routing, ok := e.stateConfig.GetInputRouting(dev.Name)
+	if !ok {
+		return configTypes.InputRouting{}
+	}
+
+	if routing.Channel < 0 || routing.ChannelCount() > dev.MaxInputChannels {
+		e.logger.Warn("saved input channel not available, using first channel",
+			keys.DeviceName(dev.Name),
+			keys.AudioChannel(routing.Channel+1),
+		)
+		return configTypes.InputRouting{}
+	}
+
+	return routing
+}
+
+func (e *Engine) resolveOutputRouting(dev *portaudio.DeviceInfo) configTypes.OutputRouting {
+	routing, ok := e.stateConfig.GetOutputRouting(dev.Name)
+	if !ok || len(routing.Channels) == 0 {
+		routing = defaultOutputRouting(e.cfg.NumChannels)
+	}
+
+	resolved := configTypes.OutputRouting{}
+	for _, ch := range routing.Channels {
+		if ch >= 0 && ch < dev.MaxOutputChannels {
+			resolved.Channels = append(resolved.Channels, ch)
+		}
+	}
+
+	if len(resolved.Channels) == 0 {
+		e.logger.Warn("saved output channels not available, using first channel", keys.DeviceName(dev.Name))
+		resolved = defaultOutputRouting(1)
+	}
+
+	return resolved
+}
+
+func (e *Engine) InputRouting() configTypes.InputRouting {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.inputRouting
+}
+
+func (e *Engine) OutputRouting() configTypes.OutputRouting {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.outputRouting
+}
+
+func (e *Engine) SetInputRouting(routing configTypes.InputRouting) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.inputDevices) == 0 {
+		return nil
+	}
+
+	e.stateConfig.SetInputRouting(e.inputDevices[e.inputIndex].Name, routing)
+
+	return e.restartStreamLocked()
+}
+
+func (e *Engine) SetOutputRouting(routing configTypes.OutputRouting) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.outputDevices) == 0 {
+		return nil
+	}
+
+	e.stateConfig.SetOutputRouting(e.outputDevices[e.outputIndex].Name, routing)
+
+	return e.restartStreamLocked()
+}
+
+func (e *Engine) restartStreamLocked() error {
+	e.stopStream()
+
+	if err := e.startStream(); err != nil {
+		e.logger.Error("failed to restart stream", keys.Error(err))
+		return err
+	}
+	return nil
+}
+
+func extractInput(dst *dsp.Buffer, in []float32, channels int, routing configTypes.InputRouting) {
+	frames := len(in) / channels
+
+	selected := 1
+	if routing.Pair {
+		selected = 2
+	}
+	dst.Resize(selected, frames)
+
+	for i := 0; i < selected; i++ {
+		samples := dst.Channel(i)
+		offset := routing.Channel + i
+		for f := range samples {
+			samples[f] = in[f*channels+offset]
+		}
+	}
+}
+
+func routeOutput(out []float32, src *dsp.Buffer, channels int, targets []int) {
+	clear(out)
+
+	if len(targets) < src.Channels() {
+		gain := 1 / float32(src.Channels())
+		for c := 0; c < src.Channels(); c++ {
+			for f, s := range src.Channel(c) {
+				for _, ch := range targets {
+					out[f*channels+ch] += s * gain
+				}
+			}
+		}
+		return
+	}
+
+	for i, ch := range targets {
+		samples := src.Channel(i % src.Channels())
+		for f, s := range samples {
+			out[f*channels+ch] = s
+		}
+	}
+}
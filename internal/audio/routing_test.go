@@ -0,0 +1,85 @@
+package audio
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/chloyka/gorig/internal/dsp"
+)
+
+func TestRouting(t *testing.T) {
+	t.Run("routeOutput", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			left     []float32
+			right    []float32
+			channels int
+			targets  []int
+			want     []float32
+		}{
+			{
+				name:     "should copy mono to every target",
+				left:     []float32{1, 2},
+				channels: 2,
+				targets:  []int{0, 1},
+				want:     []float32{1, 1, 2, 2},
+			},
+			{
+				name:     "should keep stereo channels apart",
+				left:     []float32{1, 2},
+				right:    []float32{3, 4},
+				channels: 2,
+				targets:  []int{0, 1},
+				want:     []float32{1, 3, 2, 4},
+			},
+			{
+				name:     "should downmix stereo to a single target",
+				left:     []float32{1, 2},
+				right:    []float32{3, 0},
+				channels: 1,
+				targets:  []int{0},
+				want:     []float32{2, 1},
+			},
+			{
+				name:     "should route to selected device channels only",
+				left:     []float32{1},
+				right:    []float32{2},
+				channels: 4,
+				targets:  []int{2, 3},
+				want:     []float32{0, 0, 1, 2},
+			},
+			{
+				name:     "should alternate stereo across extra targets",
+				left:     []float32{1},
+				right:    []float32{2},
+				channels: 4,
+				targets:  []int{0, 1, 2, 3},
+				want:     []float32{1, 2, 1, 2},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				channels := 1
+				if tt.right != nil {
+					channels = 2
+				}
+				src := dsp.NewBuffer(channels, len(tt.left))
+				copy(src.Channel(0), tt.left)
+				if tt.right != nil {
+					copy(src.Channel(1), tt.right)
+				}
+				got := make([]float32, len(tt.left)*tt.channels)
+				for i := range got {
+					got[i] = -1
+				}
+
+				routeOutput(got, src, tt.channels, tt.targets)
+
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,229 @@
+package audio
+
+import (
+	"slices"
+	"time"
+
+	"github.com/chloyka/gorig/internal/logger/keys"
+	errs "github.com/chloyka/gorig/utils/errors"
+	"github.com/gordonklaus/portaudio"
+)
+
+const (
+	deviceWatchInterval = 2 * time.Second
+
+	fallbackRescanInterval = 10 * time.Second
+
+	deviceNoticeBuffer = 8
+)
+
+type DeviceNoticeKind int
+
+const (
+	DeviceLost DeviceNoticeKind = iota
+	DeviceFallback
+	DeviceRestored
+)
+
+type watchAction int
+
+const (
+	watchIdle watchAction = iota
+	watchProbe
+	watchRecover
+)
+
+type DeviceNotice struct {
+	Kind   DeviceNoticeKind
+	Input  string
+	Output string
+	Err    error
+}
+
+func (e *Engine) DeviceNotices() <-chan DeviceNotice {
+	return e.notices
+}
+
+func (e *Engine) notify(notice DeviceNotice) {
+	select {
+	case e.notices <- notice:
+	default:
+	}
+}
+
+type deviceWatch struct {
+	lastCallbacks int64
+	lastProbe     time.Time
+}
+
+func (w *deviceWatch) next(now time.Time, callbacks int64, running, fallback bool) (watchAction, bool) {
+	stalled := running && callbacks == w.lastCallbacks
+	w.lastCallbacks = callbacks
+
+	action := nextWatchAction(running, stalled, fallback, now.Sub(w.lastProbe))
+	if action != watchIdle {
+		w.lastProbe = now
+	}
+	return action, stalled
+}
+
+func (e *Engine) watchDevices(done <-chan struct{}) {
+	ticker := time.NewTicker(deviceWatchInterval)
+	defer ticker.Stop()
+
+	watch := deviceWatch{lastCallbacks: e.monitor.callbacks.Load(), lastProbe: time.Now()}
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		e.mu.Lock()
+
+		select {
+		case <-done:
+			e.mu.Unlock()
+			return
+		default:
+		}
+
+		action, stalled := watch.next(time.Now(), e.monitor.callbacks.Load(), e.stream != nil, e.fallback)
+		if stalled {
+			e.logger.Warn("audio stream stalled, rescanning devices")
+		}
+		if action != watchIdle {
+			e.recoverDevicesLocked()
+		}
+
+		e.mu.Unlock()
+	}
+}
+
+func nextWatchAction(running, stalled, fallback bool, sinceProbe time.Duration) watchAction {
+	switch {
+	case stalled, !running:
+		return watchRecover
+	case fallback && sinceProbe >= fallbackRescanInterval:
+		return watchProbe
+	}
+	return watchIdle
+}
+
+func deviceNames(devices []*portaudio.DeviceInfo) []string {
+	names := make([]string, len(devices))
+	for i, d := range devices {
+		names[i] = d.Name
+	}
+	return names
+}
+
+func selectDevices(inputs, outputs []string, defaultInput, defaultOutput int, savedInput, savedOutput string) (int, int, bool) {
+	input, output := defaultInput, defaultOutput
+	fallback := false
+
+	if savedInput != "" {
+		if i := slices.Index(inputs, savedInput); i >= 0 {
+			input = i
+		} else {
+			fallback = true
+		}
+	}
+	if savedOutput != "" {
+		if i := slices.Index(outputs, savedOutput); i >= 0 {
+			output = i
+		} else {
+			fallback = true
+		}
+	}
+	return input, output, fallback
+}
+
+func (e *Engine) recoverDevicesLocked() {
+	if err := e.rescanDevicesLocked(); err != nil {
+		e.logger.Error("failed to rescan devices", keys.Error(err))
+		e.markLost(err)
+		return
+	}
+
+	if len(e.inputDevices) == 0 || len(e.outputDevices) == 0 {
+		e.markLost(errs.ErrAudioNoDevices)
+		return
+	}
+
+	var fallback bool
+	e.inputIndex, e.outputIndex, fallback = selectDevices(
+		deviceNames(e.inputDevices), deviceNames(e.outputDevices),
+		e.inputIndex, e.outputIndex,
+		e.stateConfig.InputDevice, e.stateConfig.OutputDevice,
+	)
+
+	if err := e.startStream(); err != nil {
+		e.logger.Error("failed to restart stream", keys.Error(err))
+		e.stopStream()
+		e.markLost(err)
+		return
+	}
+
+	wasFallback, wasLost := e.fallback, e.lost
+	e.fallback = fallback
+	e.lost = false
+
+	notice := DeviceNotice{
+		Input:  e.inputDevices[e.inputIndex].Name,
+		Output: e.outputDevices[e.outputIndex].Name,
+	}
+
+	kind, ok := recoveryNotice(fallback, wasFallback, wasLost)
+	if !ok {
+		return
+	}
+	notice.Kind = kind
+
+	if kind == DeviceFallback {
+		e.logger.Warn("saved device unavailable, using fallback",
+			keys.DeviceInputName(notice.Input),
+			keys.DeviceOutputName(notice.Output),
+		)
+	} else {
+		e.logger.Info("saved devices reconnected",
+			keys.DeviceInputName(notice.Input),
+			keys.DeviceOutputName(notice.Output),
+		)
+	}
+	e.notify(notice)
+}
+
+func recoveryNotice(fallback, wasFallback, wasLost bool) (DeviceNoticeKind, bool) {
+	switch {
+	case fallback && (!wasFallback || wasLost):
+		return DeviceFallback, true
+	case !fallback && (wasFallback || wasLost):
+		return DeviceRestored, true
+	}
+	return 0, false
+}
+
+func (e *Engine) rescanDevicesLocked() error {
+	e.stopStream()
+
+	if err := portaudio.Terminate(); err != nil {
+		e.logger.Warn("failed to terminate portaudio", keys.Error(err))
+	}
+
+	if err := portaudio.Initialize(); err != nil {
+		return errs.Wrap(errs.ErrAudioInit, err)
+	}
+
+	return e.enumerateDevices()
+}
+
+func (e *Engine) markLost(err error) {
+	if e.lost {
+		return
+	}
+
+	e.lost = true
+	e.notify(DeviceNotice{Kind: DeviceLost, Err: err})
+}
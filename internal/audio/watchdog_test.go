@@ -0,0 +1,139 @@
+package audio
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWatchdog(t *testing.T) {
+	t.Run("nextWatchAction", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			running    bool
+			stalled    bool
+			fallback   bool
+			sinceProbe time.Duration
+			want       watchAction
+		}{
+			{name: "should stay idle on a healthy saved device", running: true, sinceProbe: time.Hour, want: watchIdle},
+			{name: "should recover a stalled stream", running: true, stalled: true, want: watchRecover},
+			{name: "should recover when no stream is running", running: false, want: watchRecover},
+			{name: "should stay idle on fallback until probe interval passes", running: true, fallback: true, sinceProbe: fallbackRescanInterval - time.Second, want: watchIdle},
+			{name: "should rescan for saved device on fallback once probe interval passes", running: true, fallback: true, sinceProbe: fallbackRescanInterval, want: watchProbe},
+			{name: "should recover a stalled fallback stream without probing", running: true, stalled: true, fallback: true, sinceProbe: fallbackRescanInterval, want: watchRecover},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got := nextWatchAction(tt.running, tt.stalled, tt.fallback, tt.sinceProbe)
+
+				if got != tt.want {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("selectDevices", func(t *testing.T) {
+		inputs := []string{"Built-in Mic", "USB Audio"}
+		outputs := []string{"Built-in Output"}
+
+		tests := []struct {
+			name         string
+			savedInput   string
+			savedOutput  string
+			wantInput    int
+			wantOutput   int
+			wantFallback bool
+		}{
+			{name: "should pick saved input", savedInput: "USB Audio", wantInput: 1},
+			{name: "should fall back when any saved device is missing", savedInput: "USB Audio", savedOutput: "USB Audio", wantInput: 1, wantFallback: true},
+			{name: "should use defaults for a missing saved input", savedInput: "Interface", wantFallback: true},
+			{name: "should pick saved input and output", savedInput: "USB Audio", savedOutput: "Built-in Output", wantInput: 1},
+			{name: "should use defaults without saved devices"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				input, output, fallback := selectDevices(inputs, outputs, 0, 0, tt.savedInput, tt.savedOutput)
+
+				if input != tt.wantInput || output != tt.wantOutput || fallback != tt.wantFallback {
+					t.Errorf("got %d/%d (fallback=%v), want %d/%d (fallback=%v)",
+						input, output, fallback, tt.wantInput, tt.wantOutput, tt.wantFallback)
+				}
+			})
+		}
+	})
+
+	t.Run("deviceWatch", func(t *testing.T) {
+		t.Run("should restore the saved device once a later probe finds it", func(t *testing.T) {
+			scans := [][]string{
+				{"Built-in Mic"},
+				{"Built-in Mic"},
+				{"Built-in Mic", "USB Audio"},
+			}
+			start := time.Unix(0, 0)
+			sut := deviceWatch{lastProbe: start}
+			fallback := true
+			input := 0
+			probes := 0
+
+			for tick := 1; tick <= 15 && fallback; tick++ {
+				callbacks := int64(tick)
+				action, _ := sut.next(start.Add(time.Duration(tick)*deviceWatchInterval), callbacks, true, fallback)
+				if action == watchIdle {
+					continue
+				}
+
+				input, _, fallback = selectDevices(scans[probes], []string{"Built-in Output"}, 0, 0, "USB Audio", "")
+				probes++
+			}
+
+			if fallback || input != 1 {
+				t.Errorf("got input %d (fallback=%v), want saved input 1", input, fallback)
+			}
+			if probes != len(scans) {
+				t.Errorf("got %d probes, want %d", probes, len(scans))
+			}
+		})
+
+		t.Run("should report a stall when callbacks stop advancing", func(t *testing.T) {
+			start := time.Unix(0, 0)
+			sut := deviceWatch{lastCallbacks: 5, lastProbe: start}
+
+			got, stalled := sut.next(start.Add(deviceWatchInterval), 5, true, false)
+
+			if got != watchRecover || !stalled {
+				t.Errorf("got %v (stalled=%v), want %v (stalled=true)", got, stalled, watchRecover)
+			}
+		})
+	})
+
+	t.Run("recoveryNotice", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			fallback    bool
+			wasFallback bool
+			wasLost     bool
+			want        DeviceNoticeKind
+			wantNotice  bool
+		}{
+			{name: "should announce switch to fallback", fallback: true, want: DeviceFallback, wantNotice: true},
+			{name: "should announce fallback again after a loss", fallback: true, wasFallback: true, wasLost: true, want: DeviceFallback, wantNotice: true},
+			{name: "should stay quiet while fallback continues", fallback: true, wasFallback: true},
+			{name: "should announce saved devices back", wasFallback: true, want: DeviceRestored, wantNotice: true},
+			{name: "should announce saved devices back after a loss", wasLost: true, want: DeviceRestored, wantNotice: true},
+			{name: "should stay quiet on a routine restart"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got, ok := recoveryNotice(tt.fallback, tt.wasFallback, tt.wasLost)
+
+				if ok != tt.wantNotice || (ok && got != tt.want) {
+					t.Errorf("got %v (notice=%v), want %v (notice=%v)", got, ok, tt.want, tt.wantNotice)
+				}
+			})
+		}
+	})
+}
@@ -20,8 +20,9 @@ type AppConfig struct {
 	Logger  *configTypes.LoggerConfig
 	Effects *configTypes.EffectsConfig
 
-	State   *configTypes.StateConfig
-	Presets *configTypes.PresetsConfig
+	State    *configTypes.StateConfig
+	Presets  *configTypes.PresetsConfig
+	Recorder *configTypes.RecorderConfig
 
 	Savers []configTypes.ConfigSaver `group:"savers,flatten"`
 
@@ -49,16 +50,23 @@ func provideConfig() (AppConfig, error) {
 			Level:         "info",
 		},
 		Effects: &configTypes.EffectsConfig{
-			EffectsDir: "./effects",
+			EffectsDir:  "./effects",
+			IRDir:       "./irs",
+			CrossfadeMs: 10,
 		},
 		Presets: &configTypes.PresetsConfig{
 			Presets:      []configTypes.Preset{},
 			ActivePreset: "",
 		},
+		Recorder: &configTypes.RecorderConfig{
+			PatternsDir: "./patterns",
+			Bars:        2,
+			MidiNote:    36,
+		},
 	}
 
 	cfg.Savers = []configTypes.ConfigSaver{
-		cfg.Audio, cfg.Effects, cfg.State, cfg.Logger, cfg.Presets,
+		cfg.Audio, cfg.Effects, cfg.State, cfg.Logger, cfg.Presets, cfg.Recorder,
 	}
 
 	configPath := findConfigFile()
@@ -83,6 +91,8 @@ func provideConfig() (AppConfig, error) {
 			cfg.Audio.FramesPerBuffer = raw.Audio.FramesPerBuffer
 			cfg.Audio.NumChannels = raw.Audio.NumChannels
 			cfg.Audio.TargetLatency = raw.Audio.TargetLatency
+			cfg.Audio.SampleRateFallbacks = raw.Audio.SampleRateFallbacks
+			cfg.Audio.BufferSizeFallbacks = raw.Audio.BufferSizeFallbacks
 		}
 
 		if raw.Logger != nil {
@@ -95,17 +105,37 @@ func provideConfig() (AppConfig, error) {
 
 		if raw.Effects != nil {
 			cfg.Effects.EffectsDir = raw.Effects.EffectsDir
+			if raw.Effects.IRDir != "" {
+				cfg.Effects.IRDir = raw.Effects.IRDir
+			}
+			cfg.Effects.CrossfadeMs = raw.Effects.CrossfadeMs
+			cfg.Effects.Spillover = raw.Effects.Spillover
 		}
 
 		if raw.State != nil {
 			cfg.State.InputDevice = raw.State.InputDevice
 			cfg.State.OutputDevice = raw.State.OutputDevice
 			cfg.State.EffectsEnabled = raw.State.EffectsEnabled
+			cfg.State.RhythmBPM = raw.State.RhythmBPM
+			cfg.State.RhythmSubdivision = raw.State.RhythmSubdivision
+			cfg.State.RhythmFeel = raw.State.RhythmFeel
+			cfg.State.RhythmSwing = raw.State.RhythmSwing
+			cfg.State.InputGainDb = raw.State.InputGainDb
+			cfg.State.OutputVolumeDb = raw.State.OutputVolumeDb
+			cfg.State.InputRouting = raw.State.InputRouting
+			cfg.State.OutputRouting = raw.State.OutputRouting
 		}
 
 		if raw.Presets != nil {
 			cfg.Presets.Presets = raw.Presets.Presets
 			cfg.Presets.ActivePreset = raw.Presets.ActivePreset
+			cfg.Presets.SwitchMode = raw.Presets.SwitchMode
+		}
+
+		if raw.Recorder != nil {
+			cfg.Recorder.PatternsDir = raw.Recorder.PatternsDir
+			cfg.Recorder.Bars = raw.Recorder.Bars
+			cfg.Recorder.MidiNote = raw.Recorder.MidiNote
 		}
 
 		path := configTypes.ConfigPath(configPath)
@@ -175,6 +175,24 @@ func TestProvideConfig(t *testing.T) {
 			}
 		})
 
+		t.Run("should keep default IR dir when effects section omits it", func(t *testing.T) {
+			tmpDir := t.TempDir()
+			oldWd, _ := os.Getwd()
+			_ = os.Chdir(tmpDir)
+			defer func() { _ = os.Chdir(oldWd) }()
+
+			_ = os.WriteFile("config.json", []byte(`{"effects": {"effects_dir": "./fx"}}`), 0644)
+
+			got, err := provideConfig()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.Effects.IRDir != "./irs" {
+				t.Errorf("got IRDir=%q, want %q", got.Effects.IRDir, "./irs")
+			}
+		})
+
 		t.Run("should set ConfigPath when file loaded", func(t *testing.T) {
 			tmpDir := t.TempDir()
 			oldWd, _ := os.Getwd()
@@ -240,8 +258,8 @@ func TestProvideConfig(t *testing.T) {
 				t.Fatalf("unexpected error: %v", err)
 			}
 
-			if len(got.Savers) != 5 {
-				t.Errorf("got len(Savers)=%d, want 5", len(got.Savers))
+			if len(got.Savers) != 6 {
+				t.Errorf("got len(Savers)=%d, want 6", len(got.Savers))
 			}
 		})
 	})
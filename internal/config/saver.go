@@ -22,11 +22,12 @@ type configManager struct {
 	logger     *logger.Logger
 	cancel     context.CancelFunc
 
-	audio   *configTypes.AudioConfig
-	logCfg  *configTypes.LoggerConfig
-	effects *configTypes.EffectsConfig
-	state   *configTypes.StateConfig
-	presets *configTypes.PresetsConfig
+	audio    *configTypes.AudioConfig
+	logCfg   *configTypes.LoggerConfig
+	effects  *configTypes.EffectsConfig
+	state    *configTypes.StateConfig
+	presets  *configTypes.PresetsConfig
+	recorder *configTypes.RecorderConfig
 }
 
 type newConfigManagerParams struct {
@@ -36,11 +37,12 @@ type newConfigManagerParams struct {
 	ConfigPath *configTypes.ConfigPath
 	Configs    []configTypes.ConfigSaver `group:"savers"`
 
-	Audio   *configTypes.AudioConfig
-	LogCfg  *configTypes.LoggerConfig
-	Effects *configTypes.EffectsConfig
-	State   *configTypes.StateConfig
-	Presets *configTypes.PresetsConfig
+	Audio    *configTypes.AudioConfig
+	LogCfg   *configTypes.LoggerConfig
+	Effects  *configTypes.EffectsConfig
+	State    *configTypes.StateConfig
+	Presets  *configTypes.PresetsConfig
+	Recorder *configTypes.RecorderConfig
 }
 
 func provideConfigManager(in newConfigManagerParams) *configManager {
@@ -62,6 +64,7 @@ func provideConfigManager(in newConfigManagerParams) *configManager {
 		effects:    in.Effects,
 		state:      in.State,
 		presets:    in.Presets,
+		recorder:   in.Recorder,
 	}
 
 	loadedPathStr := ""
@@ -125,11 +128,12 @@ func (m *configManager) save() error {
 	}
 
 	rawConfig := &configTypes.RawConfig{
-		Audio:   m.audio,
-		Logger:  m.logCfg,
-		Effects: m.effects,
-		State:   m.state,
-		Presets: m.presets,
+		Audio:    m.audio,
+		Logger:   m.logCfg,
+		Effects:  m.effects,
+		State:    m.state,
+		Presets:  m.presets,
+		Recorder: m.recorder,
 	}
 
 	data, err := json.MarshalIndent(rawConfig, "", "  ")
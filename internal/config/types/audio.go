@@ -9,4 +9,14 @@ type AudioConfig struct {
 	FramesPerBuffer int           `json:"frames_per_buffer" yaml:"frames_per_buffer"`
 	NumChannels     int           `json:"num_channels" yaml:"num_channels"`
 	TargetLatency   time.Duration `json:"target_latency" yaml:"target_latency"`
+
+	SampleRateFallbacks []int `json:"sample_rate_fallbacks,omitempty" yaml:"sample_rate_fallbacks,omitempty"`
+	BufferSizeFallbacks []int `json:"frames_per_buffer_fallbacks,omitempty" yaml:"frames_per_buffer_fallbacks,omitempty"`
+}
+
+func (a *AudioConfig) SetStreamSettings(sampleRate, framesPerBuffer int, targetLatency time.Duration) {
+	a.SampleRate = sampleRate
+	a.FramesPerBuffer = framesPerBuffer
+	a.TargetLatency = targetLatency
+	a.Save()
 }
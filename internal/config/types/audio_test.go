@@ -0,0 +1,35 @@
+package configTypes
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAudioConfig(t *testing.T) {
+	t.Run("SetStreamSettings", func(t *testing.T) {
+		t.Run("should update stream settings and trigger save", func(t *testing.T) {
+			saveChan := make(chan struct{}, 1)
+			sut := &AudioConfig{SampleRate: 44100, FramesPerBuffer: 64, TargetLatency: 10 * time.Millisecond}
+			sut.SetSaveChan(saveChan)
+
+			sut.SetStreamSettings(96000, 256, 20*time.Millisecond)
+
+			if sut.SampleRate != 96000 {
+				t.Errorf("got SampleRate=%d, want 96000", sut.SampleRate)
+			}
+			if sut.FramesPerBuffer != 256 {
+				t.Errorf("got FramesPerBuffer=%d, want 256", sut.FramesPerBuffer)
+			}
+			if sut.TargetLatency != 20*time.Millisecond {
+				t.Errorf("got TargetLatency=%v, want 20ms", sut.TargetLatency)
+			}
+
+			select {
+			case <-saveChan:
+
+			default:
+				t.Error("expected save signal")
+			}
+		})
+	})
+}
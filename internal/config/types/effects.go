@@ -3,5 +3,8 @@ package configTypes
 type EffectsConfig struct {
 	configSaver
 
-	EffectsDir string `json:"effects_dir" yaml:"effects_dir"`
+	EffectsDir  string `json:"effects_dir" yaml:"effects_dir"`
+	IRDir       string `json:"ir_dir" yaml:"ir_dir"`
+	CrossfadeMs int    `json:"crossfade_ms" yaml:"crossfade_ms"`
+	Spillover   bool   `json:"spillover" yaml:"spillover"`
 }
@@ -1,8 +1,23 @@
 package configTypes
 
 type Preset struct {
-	Name        string   `json:"name" yaml:"name"`
-	EffectChain []string `json:"effect_chain" yaml:"effect_chain"`
+	Name        string         `json:"name" yaml:"name"`
+	EffectChain []string       `json:"effect_chain" yaml:"effect_chain"`
+	StereoSplit *int           `json:"stereo_split,omitempty" yaml:"stereo_split,omitempty"`
+	Slots       []SlotSettings `json:"slots,omitempty" yaml:"slots,omitempty"`
+}
+
+type SlotSettings struct {
+	Params     map[string]float64 `json:"params,omitempty" yaml:"params,omitempty"`
+	IR         string             `json:"ir,omitempty" yaml:"ir,omitempty"`
+	Oversample int                `json:"oversample,omitempty" yaml:"oversample,omitempty"`
+}
+
+func (p *Preset) Slot(i int) SlotSettings {
+	if p == nil || i < 0 || i >= len(p.Slots) {
+		return SlotSettings{}
+	}
+	return p.Slots[i]
 }
 
 type PresetsConfig struct {
@@ -10,6 +25,7 @@ type PresetsConfig struct {
 
 	Presets      []Preset `json:"presets" yaml:"presets"`
 	ActivePreset string   `json:"active_preset" yaml:"active_preset"`
+	SwitchMode   string   `json:"switch_mode" yaml:"switch_mode"`
 }
 
 func (p *PresetsConfig) SetActivePreset(name string) {
@@ -17,6 +33,11 @@ func (p *PresetsConfig) SetActivePreset(name string) {
 	p.Save()
 }
 
+func (p *PresetsConfig) SetSwitchMode(mode string) {
+	p.SwitchMode = mode
+	p.Save()
+}
+
 func (p *PresetsConfig) AddPreset(preset Preset) {
 	p.Presets = append(p.Presets, preset)
 	p.Save()
@@ -33,6 +54,19 @@ func (p *PresetsConfig) UpdatePreset(name string, chain []string) bool {
 	return false
 }
 
+func (p *PresetsConfig) UpdatePresetLayout(name string, chain []string, split *int, slots []SlotSettings) bool {
+	for i, preset := range p.Presets {
+		if preset.Name == name {
+			p.Presets[i].EffectChain = chain
+			p.Presets[i].StereoSplit = split
+			p.Presets[i].Slots = slots
+			p.Save()
+			return true
+		}
+	}
+	return false
+}
+
 func (p *PresetsConfig) DeletePreset(name string) bool {
 	for i, preset := range p.Presets {
 		if preset.Name == name {
@@ -24,6 +24,27 @@ func TestPresetsConfig(t *testing.T) {
 		})
 	})
 
+	t.Run("SetSwitchMode", func(t *testing.T) {
+		t.Run("should update switch mode and trigger save", func(t *testing.T) {
+			saveChan := make(chan struct{}, 1)
+			sut := &PresetsConfig{}
+			sut.SetSaveChan(saveChan)
+
+			sut.SetSwitchMode("bar")
+
+			if sut.SwitchMode != "bar" {
+				t.Errorf("got SwitchMode=%q, want %q", sut.SwitchMode, "bar")
+			}
+
+			select {
+			case <-saveChan:
+
+			default:
+				t.Error("expected save signal")
+			}
+		})
+	})
+
 	t.Run("AddPreset", func(t *testing.T) {
 		t.Run("should append preset to list", func(t *testing.T) {
 			sut := &PresetsConfig{Presets: []Preset{}}
@@ -54,6 +75,65 @@ func TestPresetsConfig(t *testing.T) {
 		})
 	})
 
+	t.Run("UpdatePresetLayout", func(t *testing.T) {
+		t.Run("should update chain, split and slots with a single save", func(t *testing.T) {
+			saveChan := make(chan struct{}, 3)
+			sut := &PresetsConfig{
+				Presets: []Preset{{Name: "test", EffectChain: []string{"drive"}}},
+			}
+			sut.SetSaveChan(saveChan)
+			split := 1
+
+			sut.UpdatePresetLayout("test", []string{"drive", "chorus"}, &split, []SlotSettings{{}, {Params: map[string]float64{"depth": 4}}})
+
+			got := sut.Presets[0]
+			if len(got.EffectChain) != 2 || got.StereoSplit == nil || *got.StereoSplit != 1 {
+				t.Errorf("got chain=%v split=%v, want [drive chorus] split=1", got.EffectChain, got.StereoSplit)
+			}
+			if depth := got.Slot(1).Params["depth"]; depth != 4 {
+				t.Errorf("got depth=%v, want 4", depth)
+			}
+			if len(saveChan) != 1 {
+				t.Errorf("got %d save signals, want 1", len(saveChan))
+			}
+		})
+
+		t.Run("should clear the stereo split", func(t *testing.T) {
+			split := 1
+			sut := &PresetsConfig{
+				Presets: []Preset{{Name: "test", EffectChain: []string{"drive", "chorus"}, StereoSplit: &split}},
+			}
+
+			sut.UpdatePresetLayout("test", []string{"drive", "chorus"}, nil, nil)
+
+			if got := sut.Presets[0].StereoSplit; got != nil {
+				t.Errorf("got StereoSplit=%v, want nil", *got)
+			}
+		})
+
+		t.Run("should return false for non-existent preset", func(t *testing.T) {
+			sut := &PresetsConfig{Presets: []Preset{}}
+
+			got := sut.UpdatePresetLayout("missing", nil, nil, nil)
+
+			if got {
+				t.Error("expected false")
+			}
+		})
+	})
+
+	t.Run("Slot", func(t *testing.T) {
+		t.Run("should return empty settings for slot out of range", func(t *testing.T) {
+			sut := &Preset{Name: "test"}
+
+			got := sut.Slot(3)
+
+			if got.Params != nil {
+				t.Errorf("got Params=%v, want nil", got.Params)
+			}
+		})
+	})
+
 	t.Run("UpdatePreset", func(t *testing.T) {
 		t.Run("should update existing preset chain", func(t *testing.T) {
 			sut := &PresetsConfig{
@@ -0,0 +1,13 @@
+package configTypes
+
+type RecorderConfig struct {
+	configSaver
+	PatternsDir string `json:"patterns_dir" yaml:"patterns_dir"`
+	Bars        int    `json:"bars" yaml:"bars"`
+	MidiNote    int    `json:"midi_note" yaml:"midi_note"`
+}
+
+func (r *RecorderConfig) SetBars(bars int) {
+	r.Bars = bars
+	r.Save()
+}
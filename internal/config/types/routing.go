@@ -0,0 +1,25 @@
+package configTypes
+
+type InputRouting struct {
+	Channel int  `json:"channel" yaml:"channel"`
+	Pair    bool `json:"pair" yaml:"pair"`
+}
+
+type OutputRouting struct {
+	Channels []int `json:"channels" yaml:"channels"`
+}
+
+func (r InputRouting) ChannelCount() int {
+	if r.Pair {
+		return r.Channel + 2
+	}
+	return r.Channel + 1
+}
+
+func (r OutputRouting) ChannelCount() int {
+	count := 0
+	for _, ch := range r.Channels {
+		count = max(count, ch+1)
+	}
+	return count
+}
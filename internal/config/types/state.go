@@ -9,6 +9,14 @@ type StateConfig struct {
 
 	RhythmBPM         float64 `json:"rhythm_bpm" yaml:"rhythm_bpm"`
 	RhythmSubdivision int     `json:"rhythm_subdivision" yaml:"rhythm_subdivision"`
+	RhythmFeel        string  `json:"rhythm_feel" yaml:"rhythm_feel"`
+	RhythmSwing       int     `json:"rhythm_swing" yaml:"rhythm_swing"`
+
+	InputGainDb    float64 `json:"input_gain_db" yaml:"input_gain_db"`
+	OutputVolumeDb float64 `json:"output_volume_db" yaml:"output_volume_db"`
+
+	InputRouting  map[string]InputRouting  `json:"input_routing,omitempty" yaml:"input_routing,omitempty"`
+	OutputRouting map[string]OutputRouting `json:"output_routing,omitempty" yaml:"output_routing,omitempty"`
 }
 
 func (s *StateConfig) SetInputDevice(name string) {
@@ -35,3 +43,49 @@ func (s *StateConfig) SetRhythmSubdivision(subdivision int) {
 	s.RhythmSubdivision = subdivision
 	s.Save()
 }
+
+func (s *StateConfig) SetRhythmFeel(feel string) {
+	s.RhythmFeel = feel
+	s.Save()
+}
+
+func (s *StateConfig) SetRhythmSwing(swing int) {
+	s.RhythmSwing = swing
+	s.Save()
+}
+
+func (s *StateConfig) SetInputGainDb(db float64) {
+	s.InputGainDb = db
+	s.Save()
+}
+
+func (s *StateConfig) SetOutputVolumeDb(db float64) {
+	s.OutputVolumeDb = db
+	s.Save()
+}
+
+func (s *StateConfig) GetInputRouting(device string) (InputRouting, bool) {
+	r, ok := s.InputRouting[device]
+	return r, ok
+}
+
+func (s *StateConfig) SetInputRouting(device string, routing InputRouting) {
+	if s.InputRouting == nil {
+		s.InputRouting = make(map[string]InputRouting)
+	}
+	s.InputRouting[device] = routing
+	s.Save()
+}
+
+func (s *StateConfig) GetOutputRouting(device string) (OutputRouting, bool) {
+	r, ok := s.OutputRouting[device]
+	return r, ok
+}
+
+func (s *StateConfig) SetOutputRouting(device string, routing OutputRouting) {
+	if s.OutputRouting == nil {
+		s.OutputRouting = make(map[string]OutputRouting)
+	}
+	s.OutputRouting[device] = routing
+	s.Save()
+}
@@ -84,4 +84,136 @@ func TestStateConfig(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("SetRhythmFeel", func(t *testing.T) {
+		t.Run("should update rhythm feel and trigger save", func(t *testing.T) {
+			saveChan := make(chan struct{}, 1)
+			sut := &StateConfig{}
+			sut.SetSaveChan(saveChan)
+
+			sut.SetRhythmFeel("triplet")
+
+			if sut.RhythmFeel != "triplet" {
+				t.Errorf("got RhythmFeel=%q, want %q", sut.RhythmFeel, "triplet")
+			}
+
+			select {
+			case <-saveChan:
+
+			default:
+				t.Error("expected save signal")
+			}
+		})
+	})
+
+	t.Run("SetRhythmSwing", func(t *testing.T) {
+		t.Run("should update rhythm swing and trigger save", func(t *testing.T) {
+			saveChan := make(chan struct{}, 1)
+			sut := &StateConfig{}
+			sut.SetSaveChan(saveChan)
+
+			sut.SetRhythmSwing(58)
+
+			if sut.RhythmSwing != 58 {
+				t.Errorf("got RhythmSwing=%d, want %d", sut.RhythmSwing, 58)
+			}
+
+			select {
+			case <-saveChan:
+
+			default:
+				t.Error("expected save signal")
+			}
+		})
+	})
+
+	t.Run("SetInputGainDb", func(t *testing.T) {
+		t.Run("should update input gain and trigger save", func(t *testing.T) {
+			saveChan := make(chan struct{}, 1)
+			sut := &StateConfig{}
+			sut.SetSaveChan(saveChan)
+
+			sut.SetInputGainDb(-6)
+
+			if sut.InputGainDb != -6 {
+				t.Errorf("got InputGainDb=%v, want %v", sut.InputGainDb, -6)
+			}
+
+			select {
+			case <-saveChan:
+
+			default:
+				t.Error("expected save signal")
+			}
+		})
+	})
+
+	t.Run("SetOutputVolumeDb", func(t *testing.T) {
+		t.Run("should update output volume and trigger save", func(t *testing.T) {
+			saveChan := make(chan struct{}, 1)
+			sut := &StateConfig{}
+			sut.SetSaveChan(saveChan)
+
+			sut.SetOutputVolumeDb(3)
+
+			if sut.OutputVolumeDb != 3 {
+				t.Errorf("got OutputVolumeDb=%v, want %v", sut.OutputVolumeDb, 3)
+			}
+
+			select {
+			case <-saveChan:
+
+			default:
+				t.Error("expected save signal")
+			}
+		})
+	})
+
+	t.Run("SetInputRouting", func(t *testing.T) {
+		t.Run("should store routing per device and trigger save", func(t *testing.T) {
+			saveChan := make(chan struct{}, 1)
+			sut := &StateConfig{}
+			sut.SetSaveChan(saveChan)
+			want := InputRouting{Channel: 1}
+
+			sut.SetInputRouting("Scarlett 4i4", want)
+
+			got, ok := sut.GetInputRouting("Scarlett 4i4")
+			if !ok || got != want {
+				t.Errorf("got %+v (ok=%v), want %+v", got, ok, want)
+			}
+			if _, ok := sut.GetInputRouting("Built-in"); ok {
+				t.Error("expected no routing for other device")
+			}
+
+			select {
+			case <-saveChan:
+
+			default:
+				t.Error("expected save signal")
+			}
+		})
+	})
+
+	t.Run("SetOutputRouting", func(t *testing.T) {
+		t.Run("should store routing per device and trigger save", func(t *testing.T) {
+			saveChan := make(chan struct{}, 1)
+			sut := &StateConfig{}
+			sut.SetSaveChan(saveChan)
+
+			sut.SetOutputRouting("Scarlett 4i4", OutputRouting{Channels: []int{0, 3}})
+
+			got, ok := sut.GetOutputRouting("Scarlett 4i4")
+			if !ok || got.ChannelCount() != 4 {
+				t.Errorf("got %+v (ok=%v), want channels [0 3]", got, ok)
+			}
+
+			select {
+			case <-saveChan:
+
+			default:
+				t.Error("expected save signal")
+			}
+		})
+	})
 }
@@ -0,0 +1,28 @@
+package dsp
+
+type Allpass struct {
+	buf  []float32
+	pos  int
+	gain float32
+}
+
+func NewAllpass(delay int, gain float32) *Allpass {
+	return &Allpass{buf: make([]float32, max(1, delay)), gain: gain}
+}
+
+func (a *Allpass) Process(x float32) float32 {
+	d := a.buf[a.pos]
+	w := x + a.gain*d
+	a.buf[a.pos] = w
+
+	a.pos++
+	if a.pos == len(a.buf) {
+		a.pos = 0
+	}
+	return d - a.gain*w
+}
+
+func (a *Allpass) Reset() {
+	clear(a.buf)
+	a.pos = 0
+}
@@ -0,0 +1,92 @@
+package dsp
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+type Biquad struct {
+	b0, b1, b2 float64
+	a1, a2     float64
+	z1, z2     float64
+}
+
+func biquadOmega(hz, sampleRate float64) (float64, float64) {
+	w := 2 * math.Pi * max(1, min(hz, 0.49*sampleRate)) / sampleRate
+	return math.Sin(w), math.Cos(w)
+}
+
+func (f *Biquad) set(b0, b1, b2, a0, a1, a2 float64) {
+	f.b0, f.b1, f.b2 = b0/a0, b1/a0, b2/a0
+	f.a1, f.a2 = a1/a0, a2/a0
+}
+
+func (f *Biquad) SetPassthrough() {
+	f.set(1, 0, 0, 1, 0, 0)
+}
+
+func (f *Biquad) SetLowpass(hz, q, sampleRate float64) {
+	sin, cos := biquadOmega(hz, sampleRate)
+	alpha := sin / (2 * q)
+	f.set((1-cos)/2, 1-cos, (1-cos)/2, 1+alpha, -2*cos, 1-alpha)
+}
+
+func (f *Biquad) SetHighpass(hz, q, sampleRate float64) {
+	sin, cos := biquadOmega(hz, sampleRate)
+	alpha := sin / (2 * q)
+	f.set((1+cos)/2, -(1 + cos), (1+cos)/2, 1+alpha, -2*cos, 1-alpha)
+}
+
+func (f *Biquad) SetPeaking(hz, q, gainDb, sampleRate float64) {
+	sin, cos := biquadOmega(hz, sampleRate)
+	alpha := sin / (2 * q)
+	a := math.Pow(10, gainDb/40)
+	f.set(1+alpha*a, -2*cos, 1-alpha*a, 1+alpha/a, -2*cos, 1-alpha/a)
+}
+
+func (f *Biquad) SetLowShelf(hz, gainDb, sampleRate float64) {
+	sin, cos := biquadOmega(hz, sampleRate)
+	a := math.Pow(10, gainDb/40)
+	beta := math.Sqrt(2*a) * sin
+	f.set(
+		a*((a+1)-(a-1)*cos+beta),
+		2*a*((a-1)-(a+1)*cos),
+		a*((a+1)-(a-1)*cos-beta),
+		(a+1)+(a-1)*cos+beta,
+		-2*((a-1)+(a+1)*cos),
+		(a+1)+(a-1)*cos-beta,
+	)
+}
+
+func (f *Biquad) SetHighShelf(hz, gainDb, sampleRate float64) {
+	sin, cos := biquadOmega(hz, sampleRate)
+	a := math.Pow(10, gainDb/40)
+	beta := math.Sqrt(2*a) * sin
+	f.set(
+		a*((a+1)+(a-1)*cos+beta),
+		-2*a*((a-1)+(a+1)*cos),
+		a*((a+1)+(a-1)*cos-beta),
+		(a+1)-(a-1)*cos+beta,
+		2*((a-1)-(a+1)*cos),
+		(a+1)-(a-1)*cos-beta,
+	)
+}
+
+func (f *Biquad) Process(x float32) float32 {
+	in := float64(x)
+	out := f.b0*in + f.z1
+	f.z1 = f.b1*in - f.a1*out + f.z2
+	f.z2 = f.b2*in - f.a2*out
+	return float32(out)
+}
+
+func (f *Biquad) MagnitudeDb(hz, sampleRate float64) float64 {
+	z := cmplx.Exp(complex(0, -2*math.Pi*hz/sampleRate))
+	num := complex(f.b0, 0) + complex(f.b1, 0)*z + complex(f.b2, 0)*z*z
+	den := 1 + complex(f.a1, 0)*z + complex(f.a2, 0)*z*z
+	return LinearToDb(cmplx.Abs(num / den))
+}
+
+func (f *Biquad) Reset() {
+	f.z1, f.z2 = 0, 0
+}
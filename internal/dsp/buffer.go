@@ -0,0 +1,95 @@
+package dsp
+
+const MaxChannels = 2
+
+type Buffer struct {
+	channels int
+	frames   int
+	data     [MaxChannels][]float32
+	views    [MaxChannels][]float32
+}
+
+func NewBuffer(channels, maxFrames int) *Buffer {
+	b := &Buffer{}
+	for ch := range b.data {
+		b.data[ch] = make([]float32, maxFrames)
+	}
+	b.Resize(channels, maxFrames)
+	return b
+}
+
+func (b *Buffer) Channels() int {
+	return b.channels
+}
+
+func (b *Buffer) Frames() int {
+	return b.frames
+}
+
+func (b *Buffer) Channel(ch int) []float32 {
+	return b.views[ch]
+}
+
+func (b *Buffer) Views() [][]float32 {
+	return b.views[:b.channels]
+}
+
+func (b *Buffer) Resize(channels, frames int) {
+	channels = max(1, min(channels, MaxChannels))
+
+	for ch := range b.data {
+		if cap(b.data[ch]) < frames {
+			b.data[ch] = make([]float32, frames)
+		}
+		b.views[ch] = b.data[ch][:frames]
+	}
+
+	b.channels = channels
+	b.frames = frames
+}
+
+func (b *Buffer) Slice(dst *Buffer, from, to int) {
+	dst.channels = b.channels
+	dst.frames = to - from
+	for ch := range b.views {
+		dst.data[ch] = b.data[ch][from:to]
+		dst.views[ch] = dst.data[ch]
+	}
+}
+
+func (b *Buffer) CopyFrom(src *Buffer) {
+	b.Resize(src.channels, src.frames)
+	for ch := 0; ch < src.channels; ch++ {
+		copy(b.views[ch], src.views[ch])
+	}
+}
+
+func (b *Buffer) Clear() {
+	for ch := 0; ch < b.channels; ch++ {
+		clear(b.views[ch])
+	}
+}
+
+func (b *Buffer) SplitToStereo() {
+	if b.channels != 1 {
+		return
+	}
+	b.channels = 2
+	copy(b.views[1], b.views[0])
+}
+
+func (b *Buffer) MixDown(dst []float32, offset int) {
+	if b.channels == 1 {
+		copy(dst, b.views[0][offset:])
+		return
+	}
+
+	gain := 1 / float32(b.channels)
+	for f := range dst {
+		var sum float32
+		for ch := 0; ch < b.channels; ch++ {
+			sum += b.views[ch][offset+f]
+		}
+		dst[f] = sum * gain
+	}
+}
@@ -0,0 +1,141 @@
+package dsp
+
+import (
+	"slices"
+	"testing"
+)
+
+func newFilledBuffer(channels ...[]float32) *Buffer {
+	b := NewBuffer(len(channels), len(channels[0]))
+	for ch, samples := range channels {
+		copy(b.Channel(ch), samples)
+	}
+	return b
+}
+
+func TestBuffer(t *testing.T) {
+	t.Run("Resize", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			channels     int
+			frames       int
+			wantChannels int
+		}{
+			{name: "should keep requested channel count", channels: 2, frames: 64, wantChannels: 2},
+			{name: "should clamp channel count to at least one", channels: 0, frames: 64, wantChannels: 1},
+			{name: "should clamp channel count to max channels", channels: 6, frames: 64, wantChannels: MaxChannels},
+			{name: "should grow past initial capacity", channels: 1, frames: 512, wantChannels: 1},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := NewBuffer(1, 128)
+
+				sut.Resize(tt.channels, tt.frames)
+
+				if sut.Channels() != tt.wantChannels || len(sut.Views()) != tt.wantChannels {
+					t.Errorf("got %d channels (%d views), want %d", sut.Channels(), len(sut.Views()), tt.wantChannels)
+				}
+				if sut.Frames() != tt.frames || len(sut.Channel(0)) != tt.frames {
+					t.Errorf("got %d frames, want %d", sut.Frames(), tt.frames)
+				}
+			})
+		}
+	})
+
+	t.Run("Slice", func(t *testing.T) {
+		t.Run("should share samples with the parent buffer", func(t *testing.T) {
+			sut := newFilledBuffer([]float32{1, 2, 3, 4}, []float32{5, 6, 7, 8})
+			var got Buffer
+
+			sut.Slice(&got, 1, 3)
+			got.Channel(1)[0] = 0
+
+			if got.Frames() != 2 || !slices.Equal(got.Channel(0), []float32{2, 3}) {
+				t.Errorf("got %v, want [2 3]", got.Channel(0))
+			}
+			if want := []float32{5, 0, 7, 8}; !slices.Equal(sut.Channel(1), want) {
+				t.Errorf("got %v, want %v", sut.Channel(1), want)
+			}
+		})
+	})
+
+	t.Run("SplitToStereo", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			input *Buffer
+			want  [][]float32
+		}{
+			{
+				name:  "should copy mono channel to both sides",
+				input: newFilledBuffer([]float32{1, 2}),
+				want:  [][]float32{{1, 2}, {1, 2}},
+			},
+			{
+				name:  "should leave stereo buffer untouched",
+				input: newFilledBuffer([]float32{1, 2}, []float32{3, 4}),
+				want:  [][]float32{{1, 2}, {3, 4}},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := tt.input
+
+				sut.SplitToStereo()
+
+				got := sut.Views()
+				if len(got) != len(tt.want) {
+					t.Fatalf("got %d channels, want %d", len(got), len(tt.want))
+				}
+				for ch := range got {
+					if !slices.Equal(got[ch], tt.want[ch]) {
+						t.Errorf("channel %d: got %v, want %v", ch, got[ch], tt.want[ch])
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("MixDown", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			input  *Buffer
+			offset int
+			frames int
+			want   []float32
+		}{
+			{
+				name:   "should copy mono channel",
+				input:  newFilledBuffer([]float32{1, 2, 3}),
+				frames: 3,
+				want:   []float32{1, 2, 3},
+			},
+			{
+				name:   "should average stereo channels",
+				input:  newFilledBuffer([]float32{1, 2, 3}, []float32{3, 0, -3}),
+				frames: 3,
+				want:   []float32{2, 1, 0},
+			},
+			{
+				name:   "should start at offset",
+				input:  newFilledBuffer([]float32{1, 2, 3, 4}, []float32{1, 2, 3, 4}),
+				offset: 2,
+				frames: 2,
+				want:   []float32{3, 4},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got := make([]float32, tt.frames)
+
+				tt.input.MixDown(got, tt.offset)
+
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+}
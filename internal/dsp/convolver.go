@@ -0,0 +1,107 @@
+package dsp
+
+type Convolver struct {
+	block      int
+	fft        *FFT
+	partitions [][]complex128
+
+	history [][]complex128
+	head    int
+	window  []float32
+	scratch []complex128
+	accum   []complex128
+
+	inFifo  []float32
+	outFifo []float32
+	pos     int
+}
+
+func NewConvolver(ir []float32, block int) *Convolver {
+	fft := NewFFT(2 * block)
+	size := fft.Size()
+	block = size / 2
+
+	count := max(1, (len(ir)+block-1)/block)
+	c := &Convolver{
+		block:      block,
+		fft:        fft,
+		partitions: make([][]complex128, count),
+		history:    make([][]complex128, count),
+		window:     make([]float32, size),
+		scratch:    make([]complex128, size),
+		accum:      make([]complex128, size),
+		inFifo:     make([]float32, block),
+		outFifo:    make([]float32, block),
+	}
+
+	for p := range c.partitions {
+		spectrum := make([]complex128, size)
+		for i := 0; i < block; i++ {
+			if idx := p*block + i; idx < len(ir) {
+				spectrum[i] = complex(float64(ir[idx]), 0)
+			}
+		}
+		fft.Forward(spectrum)
+		c.partitions[p] = spectrum
+		c.history[p] = make([]complex128, size)
+	}
+
+	return c
+}
+
+func (c *Convolver) Block() int {
+	return c.block
+}
+
+func (c *Convolver) LatencyFrames() int {
+	return c.block
+}
+
+func (c *Convolver) Process(samples []float32) {
+	for i, x := range samples {
+		c.inFifo[c.pos] = x
+		samples[i] = c.outFifo[c.pos]
+		c.pos++
+		if c.pos == c.block {
+			c.processBlock(c.inFifo, c.outFifo)
+			c.pos = 0
+		}
+	}
+}
+
+func (c *Convolver) processBlock(in, out []float32) {
+	copy(c.window, c.window[c.block:])
+	copy(c.window[c.block:], in)
+
+	c.head = (c.head + len(c.history) - 1) % len(c.history)
+	spectrum := c.history[c.head]
+	for i, x := range c.window {
+		spectrum[i] = complex(float64(x), 0)
+	}
+	c.fft.Forward(spectrum)
+
+	clear(c.accum)
+	for p, h := range c.partitions {
+		x := c.history[(c.head+p)%len(c.history)]
+		for i := range c.accum {
+			c.accum[i] += x[i] * h[i]
+		}
+	}
+
+	copy(c.scratch, c.accum)
+	c.fft.Inverse(c.scratch)
+
+	for i := range out {
+		out[i] = float32(real(c.scratch[c.block+i]))
+	}
+}
+
+func (c *Convolver) Reset() {
+	clear(c.window)
+	for _, h := range c.history {
+		clear(h)
+	}
+	clear(c.inFifo)
+	clear(c.outFifo)
+	c.pos = 0
+}
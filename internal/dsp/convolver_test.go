@@ -0,0 +1,95 @@
+package dsp
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func directConvolve(x, h []float32) []float32 {
+	y := make([]float32, len(x))
+	for n := range y {
+		var sum float32
+		for k, hk := range h {
+			if n-k >= 0 {
+				sum += hk * x[n-k]
+			}
+		}
+		y[n] = sum
+	}
+	return y
+}
+
+func TestConvolver(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	ir := make([]float32, 300)
+	for i := range ir {
+		ir[i] = float32(rng.NormFloat64()) * float32(math.Exp(-float64(i)/50))
+	}
+	input := make([]float32, 1024)
+	for i := range input {
+		input[i] = float32(rng.NormFloat64())
+	}
+	direct := directConvolve(input, ir)
+
+	tests := []struct {
+		name  string
+		sizes []int
+	}{
+		{name: "should delay by one block for block-sized buffers", sizes: []int{64}},
+		{name: "should delay by one block for block multiples", sizes: []int{128}},
+		{name: "should delay by one block for unaligned buffers", sizes: []int{100}},
+		{name: "should stay continuous across mixed buffer sizes", sizes: []int{128, 37, 64, 1, 91, 256, 27}},
+	}
+
+	t.Run("Process", func(t *testing.T) {
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := NewConvolver(ir, 64)
+				got := append([]float32(nil), input...)
+
+				for off, i := 0, 0; off < len(got); i++ {
+					size := tt.sizes[i%len(tt.sizes)]
+					sut.Process(got[off:min(off+size, len(got))])
+					off += size
+				}
+
+				latency := sut.LatencyFrames()
+				if latency != 64 {
+					t.Fatalf("got latency %d, want 64", latency)
+				}
+				for i := range got {
+					want := float32(0)
+					if i >= latency {
+						want = direct[i-latency]
+					}
+					if math.Abs(float64(got[i]-want)) > 1e-3 {
+						t.Fatalf("sample %d: got %v, want %v", i, got[i], want)
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestResample(t *testing.T) {
+	t.Run("should keep a low tone's amplitude when converting rates", func(t *testing.T) {
+		input := make([]float32, 4410)
+		for i := range input {
+			input[i] = float32(math.Sin(2 * math.Pi * 440 * float64(i) / 44100))
+		}
+
+		got := Resample(input, 44100, 48000)
+
+		if len(got) != 4800 {
+			t.Fatalf("got %d samples, want 4800", len(got))
+		}
+		var peak float32
+		for _, s := range got[1000:3800] {
+			peak = max(peak, s)
+		}
+		if math.Abs(float64(peak)-1) > 0.02 {
+			t.Errorf("got peak %v, want ~1", peak)
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package dsp
+
+type DelayLine struct {
+	buf []float32
+	pos int
+}
+
+func NewDelayLine(maxSamples int) *DelayLine {
+	return &DelayLine{buf: make([]float32, max(4, maxSamples+4))}
+}
+
+func (d *DelayLine) MaxDelay() float64 {
+	return float64(len(d.buf) - 3)
+}
+
+func (d *DelayLine) Write(x float32) {
+	d.buf[d.pos] = x
+	d.pos++
+	if d.pos == len(d.buf) {
+		d.pos = 0
+	}
+}
+
+func (d *DelayLine) Read(delay float64) float32 {
+	delay = max(1, min(delay, d.MaxDelay()))
+
+	whole := int(delay)
+	frac := float32(delay - float64(whole))
+
+	x0 := d.tap(whole)
+	xm1 := x0
+	if whole > 1 {
+		xm1 = d.tap(whole - 1)
+	}
+	x1 := d.tap(whole + 1)
+	x2 := d.tap(whole + 2)
+
+	c1 := 0.5 * (x1 - xm1)
+	c2 := xm1 - 2.5*x0 + 2*x1 - 0.5*x2
+	c3 := 0.5*(x2-xm1) + 1.5*(x0-x1)
+	return ((c3*frac+c2)*frac+c1)*frac + x0
+}
+
+func (d *DelayLine) tap(delay int) float32 {
+	i := d.pos - delay
+	if i < 0 {
+		i += len(d.buf)
+	}
+	return d.buf[i]
+}
+
+func (d *DelayLine) Reset() {
+	clear(d.buf)
+	d.pos = 0
+}
@@ -0,0 +1,64 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func newRampDelayLine(maxSamples, writes int) *DelayLine {
+	d := NewDelayLine(maxSamples)
+	for i := range writes {
+		d.Write(float32(i))
+	}
+	return d
+}
+
+func TestDelayLine(t *testing.T) {
+	t.Run("Read", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			delay float64
+			want  float32
+		}{
+			{name: "should return the sample written one step ago", delay: 1, want: 99},
+			{name: "should return the sample written the given whole steps ago", delay: 10, want: 90},
+			{name: "should interpolate between samples at a fractional delay", delay: 10.25, want: 89.75},
+			{name: "should clamp delays below one sample", delay: 0.2, want: 99},
+			{name: "should clamp delays past the buffer length", delay: 1000, want: 100 - 65},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := newRampDelayLine(64, 100)
+
+				got := sut.Read(tt.delay)
+
+				if math.Abs(float64(got-tt.want)) > 1e-4 {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("MaxDelay", func(t *testing.T) {
+		t.Run("should allow at least the requested delay", func(t *testing.T) {
+			sut := NewDelayLine(64)
+
+			if got := sut.MaxDelay(); got < 64 {
+				t.Errorf("got %v, want >= 64", got)
+			}
+		})
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		t.Run("should clear previously written samples", func(t *testing.T) {
+			sut := newRampDelayLine(64, 100)
+
+			sut.Reset()
+
+			if got := sut.Read(10); got != 0 {
+				t.Errorf("got %v, want silence", got)
+			}
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package dsp
+
+import "math"
+
+func SmoothingCoeff(ms, sampleRate float64) float32 {
+	samples := ms * sampleRate / 1000
+	if samples <= 0 {
+		return 1
+	}
+	return float32(1 - math.Exp(-1/samples))
+}
+
+type Envelope struct {
+	attack  float32
+	release float32
+	value   float32
+}
+
+func (e *Envelope) SetTimes(attackMs, releaseMs, sampleRate float64) {
+	e.attack = SmoothingCoeff(attackMs, sampleRate)
+	e.release = SmoothingCoeff(releaseMs, sampleRate)
+}
+
+func (e *Envelope) Process(x float32) float32 {
+	if x < 0 {
+		x = -x
+	}
+	if x > e.value {
+		e.value += e.attack * (x - e.value)
+	} else {
+		e.value += e.release * (x - e.value)
+	}
+	return e.value
+}
+
+func (e *Envelope) Value() float32 {
+	return e.value
+}
+
+func (e *Envelope) Reset() {
+	e.value = 0
+}
+
+func LinearToDb(x float64) float64 {
+	if x <= 1e-10 {
+		return -200
+	}
+	return 20 * math.Log10(x)
+}
+
+func DbToLinear(db float64) float64 {
+	return math.Pow(10, db/20)
+}
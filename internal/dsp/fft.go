@@ -0,0 +1,77 @@
+package dsp
+
+import (
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+type FFT struct {
+	size     int
+	twiddles []complex128
+	reversed []int
+}
+
+func NewFFT(size int) *FFT {
+	if size < 2 || size&(size-1) != 0 {
+		size = 1 << bits.Len(uint(size-1))
+	}
+
+	f := &FFT{
+		size:     size,
+		twiddles: make([]complex128, size/2),
+		reversed: make([]int, size),
+	}
+
+	for i := range f.twiddles {
+		f.twiddles[i] = cmplx.Exp(complex(0, -2*math.Pi*float64(i)/float64(size)))
+	}
+
+	shift := 64 - bits.Len(uint(size-1))
+	for i := range f.reversed {
+		f.reversed[i] = int(bits.Reverse64(uint64(i)) >> shift)
+	}
+
+	return f
+}
+
+func (f *FFT) Size() int {
+	return f.size
+}
+
+func (f *FFT) Forward(data []complex128) {
+	f.transform(data, false)
+}
+
+func (f *FFT) Inverse(data []complex128) {
+	f.transform(data, true)
+
+	scale := complex(1/float64(f.size), 0)
+	for i := range data {
+		data[i] *= scale
+	}
+}
+
+func (f *FFT) transform(data []complex128, inverse bool) {
+	for i, j := range f.reversed {
+		if i < j {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+
+	for half := 1; half < f.size; half <<= 1 {
+		step := f.size / (half << 1)
+		for start := 0; start < f.size; start += half << 1 {
+			for k := 0; k < half; k++ {
+				w := f.twiddles[k*step]
+				if inverse {
+					w = cmplx.Conj(w)
+				}
+				a := data[start+k]
+				b := data[start+k+half] * w
+				data[start+k] = a + b
+				data[start+k+half] = a - b
+			}
+		}
+	}
+}
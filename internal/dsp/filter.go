@@ -0,0 +1,26 @@
+package dsp
+
+import "math"
+
+type OnePole struct {
+	coeff float32
+	state float32
+}
+
+func (f *OnePole) SetCutoff(hz, sampleRate float64) {
+	hz = max(1, min(hz, 0.45*sampleRate))
+	f.coeff = float32(1 - math.Exp(-2*math.Pi*hz/sampleRate))
+}
+
+func (f *OnePole) Lowpass(x float32) float32 {
+	f.state += f.coeff * (x - f.state)
+	return f.state
+}
+
+func (f *OnePole) Highpass(x float32) float32 {
+	return x - f.Lowpass(x)
+}
+
+func (f *OnePole) Reset() {
+	f.state = 0
+}
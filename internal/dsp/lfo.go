@@ -0,0 +1,35 @@
+package dsp
+
+import "math"
+
+type LFO struct {
+	phase float64
+}
+
+func (l *LFO) Advance(hz, sampleRate float64) {
+	l.phase += hz / sampleRate
+	l.phase -= math.Floor(l.phase)
+}
+
+func (l *LFO) Sine(offset float64) float32 {
+	return float32(math.Sin(2 * math.Pi * (l.phase + offset)))
+}
+
+func (l *LFO) Reset() {
+	l.phase = 0
+}
+
+func (l *LFO) Triangle(offset float64) float32 {
+	p := l.phase + offset
+	p -= math.Floor(p)
+	return float32(1 - 4*math.Abs(p-0.5))
+}
+
+func (l *LFO) Square(offset float64) float32 {
+	p := l.phase + offset
+	p -= math.Floor(p)
+	if p < 0.5 {
+		return 1
+	}
+	return -1
+}
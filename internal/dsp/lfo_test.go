@@ -0,0 +1,77 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLFO(t *testing.T) {
+	t.Run("Advance", func(t *testing.T) {
+		t.Run("should step phase by rate over sample rate", func(t *testing.T) {
+			var sut LFO
+
+			sut.Advance(1, 4)
+
+			if got := sut.Sine(0); math.Abs(float64(got)-1) > 1e-6 {
+				t.Errorf("got %v, want 1 a quarter cycle in", got)
+			}
+		})
+
+		t.Run("should wrap phase after a full cycle", func(t *testing.T) {
+			var sut LFO
+
+			for range 5 {
+				sut.Advance(1, 4)
+			}
+
+			if sut.phase != 0.25 {
+				t.Errorf("got phase %v, want 0.25", sut.phase)
+			}
+		})
+	})
+
+	t.Run("Shapes", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			phase    float64
+			offset   float64
+			sine     float32
+			triangle float32
+			square   float32
+		}{
+			{name: "should start the sine at zero and the triangle at its trough", phase: 0, sine: 0, triangle: -1, square: 1},
+			{name: "should peak the triangle at half a cycle", phase: 0.5, sine: 0, triangle: 1, square: -1},
+			{name: "should reach the sine trough at three quarters", phase: 0.75, sine: -1, triangle: 0, square: -1},
+			{name: "should apply offset as extra phase", phase: 0, offset: 0.25, sine: 1, triangle: 0, square: 1},
+			{name: "should wrap offset past a full cycle", phase: 0.5, offset: 0.75, sine: 1, triangle: 0, square: 1},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := LFO{phase: tt.phase}
+
+				if got := sut.Sine(tt.offset); math.Abs(float64(got-tt.sine)) > 1e-6 {
+					t.Errorf("got sine %v, want %v", got, tt.sine)
+				}
+				if got := sut.Triangle(tt.offset); got != tt.triangle {
+					t.Errorf("got triangle %v, want %v", got, tt.triangle)
+				}
+				if got := sut.Square(tt.offset); got != tt.square {
+					t.Errorf("got square %v, want %v", got, tt.square)
+				}
+			})
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		t.Run("should restart at zero phase", func(t *testing.T) {
+			sut := LFO{phase: 0.6}
+
+			sut.Reset()
+
+			if got := sut.Triangle(0); got != -1 {
+				t.Errorf("got %v, want -1 at zero phase", got)
+			}
+		})
+	})
+}
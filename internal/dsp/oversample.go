@@ -0,0 +1,124 @@
+package dsp
+
+import "math"
+
+const (
+	oversampleTaps = 16
+
+	oversampleCutoff = 0.45
+)
+
+type Oversampler struct {
+	factor    int
+	maxFrames int
+	phases    [][]float64
+	coeffs    []float64
+	upHist    []float32
+	downHist  []float32
+}
+
+func NewOversampler(factor, maxFrames int) *Oversampler {
+	factor = max(1, factor)
+	coeffs := oversampleFilter(factor)
+
+	phases := make([][]float64, factor)
+	for p := range phases {
+		phases[p] = make([]float64, oversampleTaps+1)
+		for k := range phases[p] {
+			phases[p][k] = coeffs[k*factor+p] * float64(factor)
+		}
+	}
+
+	return &Oversampler{
+		factor:    factor,
+		maxFrames: maxFrames,
+		phases:    phases,
+		coeffs:    coeffs,
+		upHist:    make([]float32, oversampleTaps+maxFrames),
+		downHist:  make([]float32, len(coeffs)-1+factor*maxFrames),
+	}
+}
+
+func oversampleFilter(factor int) []float64 {
+	length := oversampleTaps*factor + 1
+	center := float64(length-1) / 2
+	cutoff := oversampleCutoff / float64(factor)
+
+	coeffs := make([]float64, (oversampleTaps+1)*factor)
+	var sum float64
+	for n := 0; n < length; n++ {
+		x := float64(n) - center
+		window := 0.42 - 0.5*math.Cos(2*math.Pi*float64(n)/float64(length-1)) +
+			0.08*math.Cos(4*math.Pi*float64(n)/float64(length-1))
+		coeffs[n] = 2 * cutoff * sinc(2*cutoff*x) * window
+		sum += coeffs[n]
+	}
+	for n := range coeffs {
+		coeffs[n] /= sum
+	}
+	return coeffs
+}
+
+func (o *Oversampler) Factor() int {
+	return o.factor
+}
+
+func (o *Oversampler) MaxFrames() int {
+	return o.maxFrames
+}
+
+func (o *Oversampler) LatencyFrames() int {
+	if o.factor == 1 {
+		return 0
+	}
+	return oversampleTaps
+}
+
+func (o *Oversampler) Up(dst, src []float32) {
+	if o.factor == 1 {
+		copy(dst, src)
+		return
+	}
+
+	history := oversampleTaps
+	copy(o.upHist[history:], src)
+
+	for n := range src {
+		x := o.upHist[n : n+history+1]
+		for p, phase := range o.phases {
+			var sum float64
+			for k, c := range phase {
+				sum += c * float64(x[history-k])
+			}
+			dst[n*o.factor+p] = float32(sum)
+		}
+	}
+
+	copy(o.upHist, o.upHist[len(src):len(src)+history])
+}
+
+func (o *Oversampler) Down(dst, src []float32) {
+	if o.factor == 1 {
+		copy(dst, src)
+		return
+	}
+
+	history := len(o.coeffs) - 1
+	copy(o.downHist[history:], src)
+
+	for m := range dst {
+		x := o.downHist[m*o.factor : m*o.factor+history+1]
+		var sum float64
+		for j, c := range o.coeffs {
+			sum += c * float64(x[history-j])
+		}
+		dst[m] = float32(sum)
+	}
+
+	copy(o.downHist, o.downHist[len(src):len(src)+history])
+}
+
+func (o *Oversampler) Reset() {
+	clear(o.upHist)
+	clear(o.downHist)
+}
@@ -0,0 +1,39 @@
+package dsp
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestOversampler(t *testing.T) {
+	const (
+		sampleRate = 48000
+		block      = 64
+	)
+
+	input := make([]float32, 1024)
+	for i := range input {
+		input[i] = float32(0.5 * math.Sin(2*math.Pi*1000*float64(i)/sampleRate))
+	}
+
+	for _, factor := range []int{2, 4, 8} {
+		t.Run("should round-trip a sine delayed by the reported latency at "+strconv.Itoa(factor)+"x", func(t *testing.T) {
+			sut := NewOversampler(factor, block)
+			high := make([]float32, block*factor)
+			got := make([]float32, len(input))
+
+			for off := 0; off < len(input); off += block {
+				sut.Up(high, input[off:off+block])
+				sut.Down(got[off:off+block], high)
+			}
+
+			latency := sut.LatencyFrames()
+			for i := 4 * latency; i < len(got); i++ {
+				if diff := math.Abs(float64(got[i] - input[i-latency])); diff > 1e-3 {
+					t.Fatalf("got %v at %d, want %v", got[i], i, input[i-latency])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package dsp
+
+import "math"
+
+const (
+	pitchDecimation = 4
+
+	pitchWindow = 256
+
+	pitchHop = 64
+
+	pitchThreshold = 0.15
+
+	pitchMinEnergy = 1e-6
+)
+
+type PitchDetector struct {
+	sampleRate float64
+	minLag     int
+	maxLag     int
+
+	buf  []float32
+	fill int
+	diff []float64
+
+	acc  float32
+	accN int
+
+	hz      float64
+	clarity float64
+}
+
+func NewPitchDetector(sampleRate, minHz, maxHz float64) *PitchDetector {
+	rate := sampleRate / pitchDecimation
+	minLag := max(2, int(rate/maxHz))
+	maxLag := int(math.Ceil(rate / minHz))
+
+	return &PitchDetector{
+		sampleRate: rate,
+		minLag:     minLag,
+		maxLag:     maxLag,
+		buf:        make([]float32, pitchWindow+maxLag+1),
+		diff:       make([]float64, maxLag+2),
+	}
+}
+
+func (d *PitchDetector) Push(x float32) {
+	d.acc += x
+	d.accN++
+	if d.accN < pitchDecimation {
+		return
+	}
+
+	d.buf[d.fill] = d.acc / pitchDecimation
+	d.acc, d.accN = 0, 0
+	d.fill++
+
+	if d.fill == len(d.buf) {
+		d.analyze()
+		copy(d.buf, d.buf[pitchHop:])
+		d.fill -= pitchHop
+	}
+}
+
+func (d *PitchDetector) Pitch() (hz float64, voiced bool) {
+	return d.hz, d.hz > 0
+}
+
+func (d *PitchDetector) LatencyFrames() int {
+	return (len(d.buf) + pitchHop) * pitchDecimation
+}
+
+func (d *PitchDetector) Clarity() float64 {
+	return d.clarity
+}
+
+func (d *PitchDetector) analyze() {
+	var energy float64
+	for _, s := range d.buf[:pitchWindow] {
+		energy += float64(s) * float64(s)
+	}
+	if energy/pitchWindow < pitchMinEnergy {
+		d.hz, d.clarity = 0, 0
+		return
+	}
+
+	d.diff[0] = 1
+	var running float64
+	for lag := 1; lag <= d.maxLag+1; lag++ {
+		var sum float64
+		for j := 0; j < pitchWindow; j++ {
+			delta := float64(d.buf[j] - d.buf[j+lag])
+			sum += delta * delta
+		}
+		running += sum
+		if running == 0 {
+			d.diff[lag] = 1
+			continue
+		}
+		d.diff[lag] = sum * float64(lag) / running
+	}
+
+	lag := -1
+	for tau := d.minLag; tau <= d.maxLag; tau++ {
+		if d.diff[tau] < pitchThreshold {
+			for tau+1 <= d.maxLag && d.diff[tau+1] < d.diff[tau] {
+				tau++
+			}
+			lag = tau
+			break
+		}
+	}
+	if lag < 0 {
+		d.hz, d.clarity = 0, 0
+		return
+	}
+
+	refined := float64(lag)
+	if lag > 1 && lag <= d.maxLag {
+		prev, cur, next := d.diff[lag-1], d.diff[lag], d.diff[lag+1]
+		if denom := prev - 2*cur + next; denom != 0 {
+			refined += 0.5 * (prev - next) / denom
+		}
+	}
+
+	d.hz = d.sampleRate / refined
+	d.clarity = 1 - d.diff[lag]
+}
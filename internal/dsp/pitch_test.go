@@ -0,0 +1,65 @@
+package dsp
+
+import (
+	"math"
+	"testing"
+)
+
+func sineWave(hz, sampleRate float64, n int) []float32 {
+	samples := make([]float32, n)
+	for i := range samples {
+		samples[i] = float32(0.5 * math.Sin(2*math.Pi*hz*float64(i)/sampleRate))
+	}
+	return samples
+}
+
+func detect(samples []float32, sampleRate float64) float64 {
+	d := NewPitchDetector(sampleRate, 70, 1200)
+	for _, s := range samples {
+		d.Push(s)
+	}
+	hz, _ := d.Pitch()
+	return hz
+}
+
+func TestPitchDetector(t *testing.T) {
+	t.Run("Pitch", func(t *testing.T) {
+		tests := []struct {
+			name string
+			hz   float64
+		}{
+			{name: "should detect the fundamental of an 82.4 Hz low E", hz: 82.4},
+			{name: "should detect the fundamental of a 110 Hz A", hz: 110},
+			{name: "should detect the fundamental of a 329.6 Hz high E", hz: 329.6},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got := detect(sineWave(tt.hz, 48000, 9600), 48000)
+
+				if math.Abs(got-tt.hz)/tt.hz > 0.01 {
+					t.Errorf("got %.1f Hz, want %.1f", got, tt.hz)
+				}
+			})
+		}
+	})
+}
+
+func TestPitchShifter(t *testing.T) {
+	t.Run("Process", func(t *testing.T) {
+		t.Run("should shift a 220 Hz sine up a fifth to 330 Hz", func(t *testing.T) {
+			sut := NewPitchShifter(1200)
+			sut.SetGrain(5 * 48000 / 220.0)
+			input := sineWave(220, 48000, 19200)
+			got := make([]float32, len(input))
+
+			for i, x := range input {
+				got[i] = sut.Process(x, 1.5)
+			}
+
+			if hz := detect(got, 48000); math.Abs(hz-330)/330 > 0.02 {
+				t.Errorf("got %.1f Hz, want 330", hz)
+			}
+		})
+	})
+}
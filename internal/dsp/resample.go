@@ -0,0 +1,40 @@
+package dsp
+
+import "math"
+
+const resampleTaps = 32
+
+func Resample(samples []float32, fromRate, toRate int) []float32 {
+	if fromRate == toRate || fromRate <= 0 || toRate <= 0 || len(samples) == 0 {
+		return samples
+	}
+
+	ratio := float64(toRate) / float64(fromRate)
+	cutoff := min(1, ratio)
+	out := make([]float32, int(math.Ceil(float64(len(samples))*ratio)))
+
+	for n := range out {
+		t := float64(n) / ratio
+		center := int(math.Floor(t))
+
+		var sum float64
+		for k := center - resampleTaps + 1; k <= center+resampleTaps; k++ {
+			if k < 0 || k >= len(samples) {
+				continue
+			}
+			x := t - float64(k)
+			window := 0.5 + 0.5*math.Cos(math.Pi*x/resampleTaps)
+			sum += float64(samples[k]) * cutoff * sinc(cutoff*x) * window
+		}
+		out[n] = float32(sum)
+	}
+
+	return out
+}
+
+func sinc(x float64) float64 {
+	if x == 0 {
+		return 1
+	}
+	return math.Sin(math.Pi*x) / (math.Pi * x)
+}
@@ -0,0 +1,50 @@
+package dsp
+
+import "math"
+
+const shifterGrainGlide = 0.001
+
+type PitchShifter struct {
+	line     *DelayLine
+	maxGrain float64
+	grain    float64
+	target   float64
+	phase    float64
+}
+
+func NewPitchShifter(maxGrainSamples int) *PitchShifter {
+	return &PitchShifter{
+		line:     NewDelayLine(maxGrainSamples + 2),
+		maxGrain: float64(maxGrainSamples),
+		grain:    float64(maxGrainSamples),
+		target:   float64(maxGrainSamples),
+	}
+}
+
+func (p *PitchShifter) SetGrain(samples float64) {
+	p.target = max(2, min(samples, p.maxGrain))
+}
+
+func (p *PitchShifter) Process(x float32, ratio float64) float32 {
+	p.line.Write(x)
+	p.grain += (p.target - p.grain) * shifterGrainGlide
+
+	p.phase += (1 - ratio) / p.grain
+	p.phase -= math.Floor(p.phase)
+
+	second := p.phase + 0.5
+	if second >= 1 {
+		second--
+	}
+
+	a := p.line.Read(1 + p.phase*p.grain)
+	b := p.line.Read(1 + second*p.grain)
+
+	wa := math.Sin(math.Pi * p.phase)
+	return a*float32(wa*wa) + b*float32(1-wa*wa)
+}
+
+func (p *PitchShifter) Reset() {
+	p.line.Reset()
+	p.phase = 0
+}
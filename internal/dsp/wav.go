@@ -0,0 +1,100 @@
+package dsp
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+
+	errs "github.com/chloyka/gorig/utils/errors"
+)
+
+const (
+	wavFormatPCM   = 1
+	wavFormatFloat = 3
+	wavExtensible  = 0xFFFE
+)
+
+func ReadWAVMono(path string) ([]float32, int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, errs.Wrap(errs.ErrWAVRead, err)
+	}
+
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, 0, errs.Wrap(errs.ErrWAVFormat, path)
+	}
+
+	var format, channels, bitsPerSample int
+	var sampleRate int
+	var samples []byte
+
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		body := data[pos+8 : min(pos+8+size, len(data))]
+
+		switch id {
+		case "fmt ":
+			if len(body) < 16 {
+				return nil, 0, errs.Wrap(errs.ErrWAVFormat, path)
+			}
+			format = int(binary.LittleEndian.Uint16(body[0:2]))
+			channels = int(binary.LittleEndian.Uint16(body[2:4]))
+			sampleRate = int(binary.LittleEndian.Uint32(body[4:8]))
+			bitsPerSample = int(binary.LittleEndian.Uint16(body[14:16]))
+			if format == wavExtensible && len(body) >= 26 {
+				format = int(binary.LittleEndian.Uint16(body[24:26]))
+			}
+		case "data":
+			samples = body
+		}
+
+		pos += 8 + size + size%2
+	}
+
+	if channels == 0 || samples == nil {
+		return nil, 0, errs.Wrap(errs.ErrWAVFormat, path)
+	}
+
+	decode, ok := wavDecoder(format, bitsPerSample)
+	if !ok {
+		return nil, 0, errs.Wrap(errs.ErrWAVFormat, path)
+	}
+
+	width := bitsPerSample / 8
+	frames := len(samples) / (width * channels)
+	out := make([]float32, frames)
+	for f := range out {
+		var sum float32
+		for ch := 0; ch < channels; ch++ {
+			offset := (f*channels + ch) * width
+			sum += decode(samples[offset : offset+width])
+		}
+		out[f] = sum / float32(channels)
+	}
+
+	return out, sampleRate, nil
+}
+
+func wavDecoder(format, bitsPerSample int) (func([]byte) float32, bool) {
+	switch {
+	case format == wavFormatPCM && bitsPerSample == 16:
+		return func(b []byte) float32 {
+			return float32(int16(binary.LittleEndian.Uint16(b))) / 32768
+		}, true
+	case format == wavFormatPCM && bitsPerSample == 24:
+		return func(b []byte) float32 {
+			v := int32(b[0]) | int32(b[1])<<8 | int32(int8(b[2]))<<16
+			return float32(v) / 8388608
+		}, true
+	case format == wavFormatPCM && bitsPerSample == 32:
+		return func(b []byte) float32 {
+			return float32(int32(binary.LittleEndian.Uint32(b))) / 2147483648
+		}, true
+	case format == wavFormatFloat && bitsPerSample == 32:
+		return func(b []byte) float32 {
+			return math.Float32frombits(binary.LittleEndian.Uint32(b))
+		}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,180 @@
+package effects
+
+import (
+	"math"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+
+	configTypes "github.com/chloyka/gorig/internal/config/types"
+	errs "github.com/chloyka/gorig/utils/errors"
+)
+
+type ProcessContext struct {
+	SampleRate int
+	Dry        [][]float32
+	BPM        float64
+	Onset      bool
+}
+
+type Processor interface {
+	Process(ctx *ProcessContext, channels [][]float32)
+}
+
+type GainReducer interface {
+	GainReductionDb() float64
+}
+
+type LatencyReporter interface {
+	LatencyFrames() int
+}
+
+type BuiltinConfig struct {
+	SampleRate int
+	MaxFrames  int
+	Params     *ParamValues
+	IRPath     string
+}
+
+type BuiltinFactory func(cfg BuiltinConfig) (Processor, error)
+
+type ResponseFunc func(params *ParamValues, sampleRate int, freqs []float64) []float64
+
+type BuiltinSpec struct {
+	Name     string
+	Params   []Param
+	UsesIR   bool
+	Factory  BuiltinFactory
+	Response ResponseFunc
+}
+
+var builtins = make(map[string]BuiltinSpec)
+
+func RegisterBuiltin(spec BuiltinSpec) {
+	builtins[spec.Name] = spec
+}
+
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func BuiltinParams(name string) []Param {
+	return builtins[name].Params
+}
+
+func BuiltinUsesIR(name string) bool {
+	return builtins[name].UsesIR
+}
+
+func BuiltinHasResponse(name string) bool {
+	return builtins[name].Response != nil
+}
+
+func BuiltinResponse(name string, values map[string]float64, sampleRate int, freqs []float64) []float64 {
+	spec, ok := builtins[name]
+	if !ok || spec.Response == nil {
+		return nil
+	}
+	return spec.Response(NewParamValues(spec.Params, values), sampleRate, freqs)
+}
+
+func BuiltinProcessor(name string, values map[string]float64, sampleRate, maxFrames int) (Processor, error) {
+	spec, ok := builtins[name]
+	if !ok {
+		return nil, errs.Wrap(errs.ErrEffectsNotFound, name)
+	}
+	return spec.Factory(BuiltinConfig{
+		SampleRate: sampleRate,
+		MaxFrames:  maxFrames,
+		Params:     NewParamValues(spec.Params, values),
+	})
+}
+
+type BuiltinEffect struct {
+	name   string
+	params *ParamValues
+	proc   Processor
+	ctx    *ProcessContext
+
+	timer
+}
+
+func newBuiltinEffect(spec BuiltinSpec, ctx *ProcessContext, maxFrames int, slot configTypes.SlotSettings, irDir string) (*BuiltinEffect, error) {
+	params := NewParamValues(spec.Params, slot.Params)
+
+	cfg := BuiltinConfig{SampleRate: ctx.SampleRate, MaxFrames: maxFrames, Params: params}
+	if slot.IR != "" {
+		if !filepath.IsLocal(slot.IR) {
+			return nil, errs.Wrap(errs.ErrEffectsIRPath, slot.IR)
+		}
+		cfg.IRPath = filepath.Join(irDir, slot.IR)
+	}
+
+	proc, err := spec.Factory(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BuiltinEffect{
+		name:   spec.Name,
+		params: params,
+		proc:   proc,
+		ctx:    ctx,
+	}, nil
+}
+
+func (e *BuiltinEffect) Name() string {
+	return e.name
+}
+
+func (e *BuiltinEffect) ChannelAware() bool {
+	return true
+}
+
+func (e *BuiltinEffect) Process(samples []float32) {
+	e.proc.Process(e.ctx, [][]float32{samples})
+}
+
+func (e *BuiltinEffect) ProcessChannels(channels [][]float32) {
+	e.proc.Process(e.ctx, channels)
+}
+
+func (e *BuiltinEffect) Params() *ParamValues {
+	return e.params
+}
+
+func (e *BuiltinEffect) Timing() EffectTiming {
+	return e.timing(e.name)
+}
+
+func (e *BuiltinEffect) LatencyFrames() int {
+	if reporter, ok := e.proc.(LatencyReporter); ok {
+		return reporter.LatencyFrames()
+	}
+	return 0
+}
+
+func (e *BuiltinEffect) GainReductionDb() (float64, bool) {
+	reducer, ok := e.proc.(GainReducer)
+	if !ok {
+		return 0, false
+	}
+	return reducer.GainReductionDb(), true
+}
+
+type Meter struct {
+	bits atomic.Uint64
+}
+
+func (m *Meter) Store(v float64) {
+	m.bits.Store(math.Float64bits(v))
+}
+
+func (m *Meter) Load() float64 {
+	return math.Float64frombits(m.bits.Load())
+}
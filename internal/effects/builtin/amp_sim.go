@@ -0,0 +1,177 @@
+package builtin
+
+import (
+	"math"
+
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const (
+	ampGain = iota
+	ampBass
+	ampMid
+	ampTreble
+	ampPresence
+	ampSag
+	ampMaster
+)
+
+const (
+	ampOversample = 4
+
+	ampBlock = 64
+
+	ampInputHighpassHz = 70
+
+	ampMaxDriveDb = 45
+
+	ampPresenceHz = 3000
+
+	ampMaxPresenceDb = 12
+
+	ampSagAttackMs = 5
+
+	ampSagReleaseMs = 120
+
+	ampPowerDrive = 1.5
+)
+
+var ampStages = []struct {
+	bias       float32
+	couplingHz float64
+	millerHz   float64
+}{
+	{0.3, 30, 9000},
+	{-0.2, 120, 7000},
+	{0.25, 60, 5500},
+}
+
+var ampSimParams = []effects.Param{
+	{Name: "gain", Min: 0, Max: 10, Default: 5, Step: 0.5},
+	{Name: "bass", Min: 0, Max: 10, Default: 5, Step: 0.5},
+	{Name: "mid", Min: 0, Max: 10, Default: 5, Step: 0.5},
+	{Name: "treble", Min: 0, Max: 10, Default: 5, Step: 0.5},
+	{Name: "presence", Min: 0, Max: 10, Default: 3, Step: 0.5},
+	{Name: "sag", Min: 0, Max: 10, Default: 3, Step: 0.5},
+	{Name: "master", Min: -24, Max: 12, Default: -6, Step: 0.5, Unit: "dB"},
+}
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:    "amp sim",
+		Params:  ampSimParams,
+		Factory: newAmpSim,
+	})
+}
+
+type ampStage struct {
+	coupling dsp.OnePole
+	miller   dsp.OnePole
+}
+
+type ampChannel struct {
+	sampler  *dsp.Oversampler
+	inputHP  dsp.Biquad
+	stages   []ampStage
+	stack    toneStack
+	presence dsp.Biquad
+	sag      dsp.Envelope
+}
+
+type ampSim struct {
+	sampleRate float64
+	params     *effects.ParamValues
+	channels   [dsp.MaxChannels]*ampChannel
+	high       []float32
+
+	presenceDb float64
+}
+
+func newAmpSim(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	a := &ampSim{
+		sampleRate: float64(cfg.SampleRate),
+		params:     cfg.Params,
+		high:       make([]float32, ampBlock*ampOversample),
+		presenceDb: math.NaN(),
+	}
+
+	highRate := a.sampleRate * ampOversample
+	for ch := range a.channels {
+		c := &ampChannel{
+			sampler: dsp.NewOversampler(ampOversample, ampBlock),
+			stages:  make([]ampStage, len(ampStages)),
+		}
+		c.inputHP.SetHighpass(ampInputHighpassHz, math.Sqrt2/2, a.sampleRate)
+		for i, stage := range ampStages {
+			c.stages[i].coupling.SetCutoff(stage.couplingHz, highRate)
+			c.stages[i].miller.SetCutoff(stage.millerHz, highRate)
+		}
+		c.sag.SetTimes(ampSagAttackMs, ampSagReleaseMs, highRate)
+		a.channels[ch] = c
+	}
+	return a, nil
+}
+
+func (a *ampSim) update() {
+	highRate := a.sampleRate * ampOversample
+	for _, c := range a.channels {
+		c.stack.set(a.params.Get(ampBass)/10, a.params.Get(ampMid)/10, a.params.Get(ampTreble)/10, highRate)
+	}
+
+	presence := a.params.Get(ampPresence) / 10 * ampMaxPresenceDb
+	if presence == a.presenceDb {
+		return
+	}
+	a.presenceDb = presence
+	for _, c := range a.channels {
+		c.presence.SetHighShelf(ampPresenceHz, presence, highRate)
+	}
+}
+
+func (a *ampSim) Process(_ *effects.ProcessContext, channels [][]float32) {
+	a.update()
+
+	stageGain := float32(dsp.DbToLinear(a.params.Get(ampGain) / 10 * ampMaxDriveDb / float64(len(ampStages))))
+	sag := float32(a.params.Get(ampSag) / 10)
+	master := float32(dsp.DbToLinear(a.params.Get(ampMaster)))
+	powerNorm := float32(1 / math.Tanh(ampPowerDrive))
+
+	for ch, samples := range channels {
+		c := a.channels[ch]
+
+		for i := range samples {
+			samples[i] = c.inputHP.Process(samples[i])
+		}
+
+		for off := 0; off < len(samples); off += ampBlock {
+			block := samples[off:min(off+ampBlock, len(samples))]
+			high := a.high[:len(block)*ampOversample]
+
+			c.sampler.Up(high, block)
+			for i, x := range high {
+				for s, stage := range ampStages {
+					st := &c.stages[s]
+					x = st.coupling.Highpass(x) * stageGain
+					x = tanh32(x+stage.bias) - tanh32(stage.bias)
+					x = st.miller.Lowpass(x)
+				}
+
+				x = c.presence.Process(c.stack.process(x))
+
+				level := c.sag.Process(x)
+				x *= 1 / (1 + sag*level)
+				high[i] = tanh32(ampPowerDrive*x) * powerNorm * master
+			}
+			c.sampler.Down(block, high)
+		}
+	}
+}
+
+func (a *ampSim) LatencyFrames() int {
+	return a.channels[0].sampler.LatencyFrames()
+}
+
+func tanh32(x float32) float32 {
+	return float32(math.Tanh(float64(x)))
+}
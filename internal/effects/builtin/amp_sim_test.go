@@ -0,0 +1,40 @@
+package builtin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+func TestAmpSim(t *testing.T) {
+	t.Run("Process", func(t *testing.T) {
+		t.Run("should drive a quiet signal into bounded saturation at full gain", func(t *testing.T) {
+			sut := newTestBuiltin(t, "amp sim", map[string]float64{"gain": 10, "master": 0})
+			got := sine(220, 0.02)
+
+			sut.Process(&effects.ProcessContext{SampleRate: testSampleRate}, [][]float32{got})
+
+			if db := rmsDb(got[testSampleRate/2:]); db < -12 {
+				t.Errorf("got %.1f dB RMS, want driven output above -12", db)
+			}
+			for i, s := range got {
+				if math.Abs(float64(s)) > 1.05 {
+					t.Fatalf("got %v at %d, want output bounded by the power stage", s, i)
+				}
+			}
+		})
+	})
+
+	t.Run("LatencyFrames", func(t *testing.T) {
+		t.Run("should report the internal oversampling latency", func(t *testing.T) {
+			sut := newTestBuiltin(t, "amp sim", nil)
+
+			got := sut.(effects.LatencyReporter).LatencyFrames()
+
+			if got <= 0 {
+				t.Errorf("got %d latency frames, want > 0", got)
+			}
+		})
+	})
+}
@@ -0,0 +1,56 @@
+package builtin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const (
+	testSampleRate = 48000
+
+	testFrames = 256
+)
+
+func newTestBuiltin(t *testing.T, name string, overrides map[string]float64) effects.Processor {
+	t.Helper()
+
+	sut, err := effects.BuiltinProcessor(name, overrides, testSampleRate, testFrames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sut
+}
+
+func constant(frames int, value float32) []float32 {
+	samples := make([]float32, frames)
+	for i := range samples {
+		samples[i] = value
+	}
+	return samples
+}
+
+func sine(hz, amplitude float64) []float32 {
+	samples := make([]float32, testSampleRate)
+	for i := range samples {
+		samples[i] = float32(amplitude * math.Sin(2*math.Pi*hz*float64(i)/testSampleRate))
+	}
+	return samples
+}
+
+func impulse(frames int) []float32 {
+	samples := make([]float32, frames)
+	samples[0] = 1
+	return samples
+}
+
+func peakIndex(samples []float32) int {
+	best := 0
+	for i, s := range samples {
+		if abs(s) > abs(samples[best]) {
+			best = i
+		}
+	}
+	return best
+}
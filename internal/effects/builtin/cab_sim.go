@@ -0,0 +1,114 @@
+package builtin
+
+import (
+	"math"
+
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const (
+	cabMix = iota
+	cabLevel
+)
+
+const (
+	cabPartitionSize = 64
+
+	cabMaxIRSeconds = 1.0
+)
+
+var cabSimParams = []effects.Param{
+	{Name: "mix", Min: 0, Max: 100, Default: 100, Step: 5, Unit: "%"},
+	{Name: "level", Min: -24, Max: 12, Default: 0, Step: 0.5, Unit: "dB"},
+}
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:    "cab sim",
+		Params:  cabSimParams,
+		UsesIR:  true,
+		Factory: newCabSim,
+	})
+}
+
+type cabSim struct {
+	params     *effects.ParamValues
+	convolvers [dsp.MaxChannels]*dsp.Convolver
+	dryDelays  [dsp.MaxChannels]*dsp.DelayLine
+	dry        []float32
+}
+
+func newCabSim(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	c := &cabSim{
+		params: cfg.Params,
+		dry:    make([]float32, max(cfg.MaxFrames, cabPartitionSize)),
+	}
+	if cfg.IRPath == "" {
+		return c, nil
+	}
+
+	ir, rate, err := dsp.ReadWAVMono(cfg.IRPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ir = dsp.Resample(ir, rate, cfg.SampleRate)
+	ir = ir[:min(len(ir), int(cabMaxIRSeconds*float64(cfg.SampleRate)))]
+	normalizeIR(ir)
+
+	for ch := range c.convolvers {
+		c.convolvers[ch] = dsp.NewConvolver(ir, cabPartitionSize)
+		c.dryDelays[ch] = dsp.NewDelayLine(c.convolvers[ch].LatencyFrames())
+	}
+	return c, nil
+}
+
+func normalizeIR(ir []float32) {
+	var energy float64
+	for _, s := range ir {
+		energy += float64(s) * float64(s)
+	}
+	if energy == 0 {
+		return
+	}
+
+	scale := float32(1 / math.Sqrt(energy))
+	for i := range ir {
+		ir[i] *= scale
+	}
+}
+
+func (c *cabSim) Process(_ *effects.ProcessContext, channels [][]float32) {
+	if c.convolvers[0] == nil {
+		return
+	}
+
+	wet := float32(c.params.Get(cabMix) / 100)
+	level := float32(dsp.DbToLinear(c.params.Get(cabLevel)))
+	latency := float64(c.convolvers[0].LatencyFrames())
+
+	for ch, samples := range channels {
+		for off := 0; off < len(samples); off += len(c.dry) {
+			chunk := samples[off:min(off+len(c.dry), len(samples))]
+			dry := c.dry[:len(chunk)]
+			for i, x := range chunk {
+				dry[i] = c.dryDelays[ch].Read(latency)
+				c.dryDelays[ch].Write(x)
+			}
+
+			c.convolvers[ch].Process(chunk)
+
+			for i := range chunk {
+				chunk[i] = (chunk[i]*wet + dry[i]*(1-wet)) * level
+			}
+		}
+	}
+}
+
+func (c *cabSim) LatencyFrames() int {
+	if c.convolvers[0] == nil {
+		return 0
+	}
+	return c.convolvers[0].LatencyFrames()
+}
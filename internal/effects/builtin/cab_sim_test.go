@@ -0,0 +1,127 @@
+package builtin
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+func writeTestWAV(t *testing.T, samples []int16, sampleRate int) string {
+	t.Helper()
+
+	data := make([]byte, 44+2*len(samples))
+	copy(data[0:], "RIFF")
+	binary.LittleEndian.PutUint32(data[4:], uint32(36+2*len(samples)))
+	copy(data[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(data[16:], 16)
+	binary.LittleEndian.PutUint16(data[20:], 1)
+	binary.LittleEndian.PutUint16(data[22:], 1)
+	binary.LittleEndian.PutUint32(data[24:], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(data[28:], uint32(sampleRate*2))
+	binary.LittleEndian.PutUint16(data[32:], 2)
+	binary.LittleEndian.PutUint16(data[34:], 16)
+	copy(data[36:], "data")
+	binary.LittleEndian.PutUint32(data[40:], uint32(2*len(samples)))
+	for i, s := range samples {
+		binary.LittleEndian.PutUint16(data[44+2*i:], uint16(s))
+	}
+
+	path := filepath.Join(t.TempDir(), "ir.wav")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newTestCabSim(t *testing.T, ir []int16, values map[string]float64) effects.Processor {
+	t.Helper()
+
+	sut, err := newCabSim(effects.BuiltinConfig{
+		SampleRate: 48000,
+		MaxFrames:  64,
+		Params:     effects.NewParamValues(cabSimParams, values),
+		IRPath:     writeTestWAV(t, ir, 48000),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sut
+}
+
+func TestCabSim(t *testing.T) {
+	t.Run("Process", func(t *testing.T) {
+		t.Run("should convolve input with loaded impulse response after the reported latency", func(t *testing.T) {
+			ir := make([]int16, 32)
+			ir[10] = 16384
+			sut := newTestCabSim(t, ir, nil)
+
+			got := make([]float32, 128)
+			got[0] = 1
+			sut.Process(&effects.ProcessContext{SampleRate: 48000}, [][]float32{got})
+
+			tap := sut.(*cabSim).LatencyFrames() + 10
+			if got[tap] < 0.99 || got[tap] > 1.01 {
+				t.Errorf("got %v at tap %d, want normalized impulse", got[tap], tap)
+			}
+			for i, s := range got {
+				if i != tap && math.Abs(float64(s)) > 0.01 {
+					t.Fatalf("got %v at %d, want silence", s, i)
+				}
+			}
+		})
+
+		t.Run("should keep dry and wet aligned when mixed", func(t *testing.T) {
+			ir := make([]int16, 8)
+			ir[0] = 16384
+			sut := newTestCabSim(t, ir, map[string]float64{"mix": 50})
+
+			got := make([]float32, 200)
+			got[0] = 1
+			for off := 0; off < len(got); off += 50 {
+				sut.Process(&effects.ProcessContext{SampleRate: 48000}, [][]float32{got[off : off+50]})
+			}
+
+			latency := sut.(*cabSim).LatencyFrames()
+			if math.Abs(float64(got[latency])-1) > 0.01 {
+				t.Errorf("got %v at latency %d, want dry and wet summed to 1", got[latency], latency)
+			}
+			for i, s := range got {
+				if i != latency && math.Abs(float64(s)) > 0.01 {
+					t.Fatalf("got %v at %d, want silence", s, i)
+				}
+			}
+		})
+
+		t.Run("should not allocate for buffers larger than max frames", func(t *testing.T) {
+			sut := newTestCabSim(t, make([]int16, 32), nil)
+			buf := [][]float32{make([]float32, 256), make([]float32, 256)}
+			ctx := &effects.ProcessContext{SampleRate: 48000}
+
+			got := testing.AllocsPerRun(20, func() {
+				sut.Process(ctx, buf)
+			})
+
+			if got != 0 {
+				t.Errorf("got %v allocs per run, want 0", got)
+			}
+		})
+	})
+
+	t.Run("newCabSim", func(t *testing.T) {
+		t.Run("should fail when impulse response file is missing", func(t *testing.T) {
+			_, err := newCabSim(effects.BuiltinConfig{
+				SampleRate: 48000,
+				Params:     effects.NewParamValues(cabSimParams, nil),
+				IRPath:     filepath.Join(t.TempDir(), "missing.wav"),
+			})
+
+			if err == nil {
+				t.Error("expected error")
+			}
+		})
+	})
+}
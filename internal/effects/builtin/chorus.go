@@ -0,0 +1,26 @@
+package builtin
+
+import "github.com/chloyka/gorig/internal/effects"
+
+var chorusParams = lfoParams(0.8, 5,
+	effects.Param{Name: "depth", Min: 0, Max: 10, Default: 3, Step: 0.5, Unit: "ms"},
+	effects.Param{Name: "mix", Min: 0, Max: 100, Default: 50, Step: 5, Unit: "%"},
+)
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:    "chorus",
+		Params:  chorusParams,
+		Factory: newChorus,
+	})
+}
+
+func newChorus(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	return newModDelay(cfg, modDelaySpec{
+		baseMs:      7,
+		maxDepthMs:  10,
+		stereoPhase: 0.25,
+		feedback:    -1,
+		mix:         lfoParamCount + 1,
+	}), nil
+}
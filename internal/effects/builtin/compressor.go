@@ -0,0 +1,115 @@
+package builtin
+
+import (
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const (
+	compThreshold = iota
+	compRatio
+	compKnee
+	compAttack
+	compRelease
+	compMakeup
+	compAutoMakeup
+)
+
+var compressorParams = []effects.Param{
+	{Name: "threshold", Min: -60, Max: 0, Default: -20, Step: 1, Unit: "dB"},
+	{Name: "ratio", Min: 1, Max: 20, Default: 4, Step: 0.5, Unit: ":1"},
+	{Name: "knee", Min: 0, Max: 24, Default: 6, Step: 1, Unit: "dB"},
+	{Name: "attack", Min: 0.1, Max: 100, Default: 10, Step: 0.5, Unit: "ms"},
+	{Name: "release", Min: 10, Max: 1000, Default: 120, Step: 10, Unit: "ms"},
+	{Name: "makeup", Min: 0, Max: 24, Default: 0, Step: 0.5, Unit: "dB"},
+	{Name: "auto makeup", Min: 0, Max: 1, Default: 0, Step: 1, Labels: []string{"off", "on"}},
+}
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:    "compressor",
+		Params:  compressorParams,
+		Factory: newCompressor,
+	})
+}
+
+type compressor struct {
+	sampleRate float64
+	params     *effects.ParamValues
+
+	reductionDb float64
+
+	attackMs     float64
+	releaseMs    float64
+	attackCoeff  float64
+	releaseCoeff float64
+
+	reduction effects.Meter
+}
+
+func newCompressor(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	return &compressor{sampleRate: float64(cfg.SampleRate), params: cfg.Params}, nil
+}
+
+func (c *compressor) updateCoeffs() {
+	attack, release := c.params.Get(compAttack), c.params.Get(compRelease)
+	if attack == c.attackMs && release == c.releaseMs {
+		return
+	}
+	c.attackMs, c.releaseMs = attack, release
+	c.attackCoeff = float64(dsp.SmoothingCoeff(attack, c.sampleRate))
+	c.releaseCoeff = float64(dsp.SmoothingCoeff(release, c.sampleRate))
+}
+
+func gainComputer(levelDb, threshold, ratio, knee float64) float64 {
+	over := levelDb - threshold
+	switch {
+	case 2*over <= -knee:
+		return 0
+	case 2*over < knee:
+		x := over + knee/2
+		return (1 - 1/ratio) * x * x / (2 * knee)
+	default:
+		return over * (1 - 1/ratio)
+	}
+}
+
+func (c *compressor) Process(_ *effects.ProcessContext, channels [][]float32) {
+	c.updateCoeffs()
+
+	threshold := c.params.Get(compThreshold)
+	ratio := c.params.Get(compRatio)
+	knee := c.params.Get(compKnee)
+
+	makeup := c.params.Get(compMakeup)
+	if c.params.Get(compAutoMakeup) >= 0.5 {
+		makeup += gainComputer(0, threshold, ratio, knee) / 2
+	}
+
+	peakReduction := 0.0
+	for i := range channels[0] {
+		var peak float32
+		for _, ch := range channels {
+			peak = max(peak, abs(ch[i]))
+		}
+
+		target := gainComputer(dsp.LinearToDb(float64(peak)), threshold, ratio, knee)
+		coeff := c.releaseCoeff
+		if target > c.reductionDb {
+			coeff = c.attackCoeff
+		}
+		c.reductionDb += coeff * (target - c.reductionDb)
+		peakReduction = max(peakReduction, c.reductionDb)
+
+		gain := float32(dsp.DbToLinear(makeup - c.reductionDb))
+		for _, ch := range channels {
+			ch[i] *= gain
+		}
+	}
+
+	c.reduction.Store(peakReduction)
+}
+
+func (c *compressor) GainReductionDb() float64 {
+	return c.reduction.Load()
+}
@@ -0,0 +1,62 @@
+package builtin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+func TestCompressor(t *testing.T) {
+	t.Run("Process", func(t *testing.T) {
+		t.Run("should reduce gain above threshold by the ratio", func(t *testing.T) {
+			sut := newTestBuiltin(t, "compressor", map[string]float64{"threshold": -20, "ratio": 4, "knee": 0})
+
+			var got []float32
+			for range 100 {
+				got = constant(testFrames, 1)
+				sut.Process(&effects.ProcessContext{SampleRate: testSampleRate}, [][]float32{got})
+			}
+
+			if db := dsp.LinearToDb(float64(got[testFrames-1])); math.Abs(db+15) > 0.1 {
+				t.Errorf("got %.2f dB, want -15", db)
+			}
+			if gr := sut.(effects.GainReducer).GainReductionDb(); math.Abs(gr-15) > 0.1 {
+				t.Errorf("got gain reduction %.2f dB, want 15", gr)
+			}
+		})
+
+		t.Run("should add half the reduction at 0 dBFS as auto makeup", func(t *testing.T) {
+			sut := newTestBuiltin(t, "compressor", map[string]float64{"threshold": -20, "ratio": 4, "knee": 0, "auto makeup": 1})
+
+			var got []float32
+			for range 100 {
+				got = constant(testFrames, 1)
+				sut.Process(&effects.ProcessContext{SampleRate: testSampleRate}, [][]float32{got})
+			}
+
+			if db := dsp.LinearToDb(float64(got[testFrames-1])); math.Abs(db+7.5) > 0.1 {
+				t.Errorf("got %.2f dB, want -7.5", db)
+			}
+		})
+	})
+}
+
+func TestTransientShaper(t *testing.T) {
+	t.Run("Process", func(t *testing.T) {
+		t.Run("should boost the attack of a note and settle back to unity", func(t *testing.T) {
+			sut := newTestBuiltin(t, "transient shaper", map[string]float64{"attack": 100})
+			got := constant(testSampleRate, 0.5)
+
+			sut.Process(&effects.ProcessContext{SampleRate: testSampleRate}, [][]float32{got})
+
+			if peak := got[240]; peak <= 0.6 {
+				t.Errorf("got %v at 5 ms, want boosted attack", peak)
+			}
+			if tail := got[len(got)-1]; math.Abs(float64(tail)-0.5) > 0.01 {
+				t.Errorf("got %v after 1 s, want unity", tail)
+			}
+		})
+	})
+}
@@ -0,0 +1,92 @@
+package builtin
+
+import (
+	"math"
+
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+type eqDesign func(params *effects.ParamValues, sampleRate float64, filters []dsp.Biquad)
+
+type eqSpec struct {
+	design     eqDesign
+	maxFilters int
+	level      int
+}
+
+type eq struct {
+	spec       eqSpec
+	sampleRate float64
+	params     *effects.ParamValues
+	filters    [dsp.MaxChannels][]dsp.Biquad
+	cached     []float64
+}
+
+func newEQ(cfg effects.BuiltinConfig, spec eqSpec) *eq {
+	e := &eq{
+		spec:       spec,
+		sampleRate: float64(cfg.SampleRate),
+		params:     cfg.Params,
+		cached:     make([]float64, len(cfg.Params.Defs())),
+	}
+	for ch := range e.filters {
+		e.filters[ch] = make([]dsp.Biquad, spec.maxFilters)
+	}
+	for i := range e.cached {
+		e.cached[i] = math.NaN()
+	}
+	return e
+}
+
+func (e *eq) update() {
+	changed := false
+	for i := range e.cached {
+		if v := e.params.Get(i); v != e.cached[i] {
+			e.cached[i] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	for ch := range e.filters {
+		e.spec.design(e.params, e.sampleRate, e.filters[ch])
+	}
+}
+
+func (e *eq) Process(_ *effects.ProcessContext, channels [][]float32) {
+	e.update()
+
+	level := float32(dsp.DbToLinear(e.params.Get(e.spec.level)))
+
+	for ch, samples := range channels {
+		filters := e.filters[ch]
+		for i, x := range samples {
+			for f := range filters {
+				x = filters[f].Process(x)
+			}
+			samples[i] = x * level
+		}
+	}
+}
+
+func (s eqSpec) response(params *effects.ParamValues, sampleRate int, freqs []float64) []float64 {
+	filters := make([]dsp.Biquad, s.maxFilters)
+	s.design(params, float64(sampleRate), filters)
+	level := params.Get(s.level)
+
+	out := make([]float64, len(freqs))
+	for i, hz := range freqs {
+		out[i] = level
+		for f := range filters {
+			out[i] += filters[f].MagnitudeDb(hz, float64(sampleRate))
+		}
+	}
+	return out
+}
+
+func butterworthQ(order, section int) float64 {
+	return 1 / (2 * math.Sin(float64(2*section+1)*math.Pi/float64(2*order)))
+}
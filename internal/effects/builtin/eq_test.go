@@ -0,0 +1,52 @@
+package builtin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+func TestEQ(t *testing.T) {
+	t.Run("Response", func(t *testing.T) {
+		t.Run("should report the boosted band in the graphic EQ response", func(t *testing.T) {
+			got := effects.BuiltinResponse("graphic eq", map[string]float64{"1 kHz": 6}, testSampleRate, []float64{1000, 8000})
+
+			if math.Abs(got[0]-6) > 0.1 {
+				t.Errorf("got %.2f dB at 1 kHz, want 6", got[0])
+			}
+			if math.Abs(got[1]) > 0.5 {
+				t.Errorf("got %.2f dB at 8 kHz, want flat", got[1])
+			}
+		})
+
+		t.Run("should roll off below a 24 dB/oct high-pass", func(t *testing.T) {
+			got := effects.BuiltinResponse("parametric eq", map[string]float64{"hp slope": 2, "hp freq": 100}, testSampleRate, []float64{50, 100, 1000})
+
+			if math.Abs(got[0]+24.1) > 0.5 {
+				t.Errorf("got %.2f dB an octave below cutoff, want -24", got[0])
+			}
+			if math.Abs(got[1]+3) > 0.1 {
+				t.Errorf("got %.2f dB at cutoff, want -3", got[1])
+			}
+			if math.Abs(got[2]) > 0.1 {
+				t.Errorf("got %.2f dB in the passband, want flat", got[2])
+			}
+		})
+	})
+
+	t.Run("Process", func(t *testing.T) {
+		t.Run("should process a sine by the computed response", func(t *testing.T) {
+			values := map[string]float64{"peak 1 freq": 1000, "peak 1 gain": -12}
+			sut := newTestBuiltin(t, "parametric eq", values)
+			got := sine(1000, 1)
+
+			sut.Process(&effects.ProcessContext{SampleRate: testSampleRate}, [][]float32{got})
+
+			want := effects.BuiltinResponse("parametric eq", values, testSampleRate, []float64{1000})[0]
+			if db := rmsDb(got[testSampleRate/2:]) + 3.01; math.Abs(db-want) > 0.1 {
+				t.Errorf("got %.2f dB, want %.2f", db, want)
+			}
+		})
+	})
+}
@@ -0,0 +1,27 @@
+package builtin
+
+import "github.com/chloyka/gorig/internal/effects"
+
+var flangerParams = lfoParams(0.3, 5,
+	effects.Param{Name: "depth", Min: 0, Max: 5, Default: 2, Step: 0.25, Unit: "ms"},
+	effects.Param{Name: "feedback", Min: -95, Max: 95, Default: 50, Step: 5, Unit: "%"},
+	effects.Param{Name: "mix", Min: 0, Max: 100, Default: 50, Step: 5, Unit: "%"},
+)
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:    "flanger",
+		Params:  flangerParams,
+		Factory: newFlanger,
+	})
+}
+
+func newFlanger(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	return newModDelay(cfg, modDelaySpec{
+		baseMs:      0.5,
+		maxDepthMs:  5,
+		stereoPhase: 0.25,
+		feedback:    lfoParamCount + 1,
+		mix:         lfoParamCount + 2,
+	}), nil
+}
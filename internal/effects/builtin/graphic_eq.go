@@ -0,0 +1,49 @@
+package builtin
+
+import (
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const graphicEQQ = 1.41
+
+var graphicEQBands = []float64{31, 62, 125, 250, 500, 1000, 2000, 4000, 8000, 16000}
+
+var graphicEQParams = func() []effects.Param {
+	names := []string{"31 Hz", "62 Hz", "125 Hz", "250 Hz", "500 Hz", "1 kHz", "2 kHz", "4 kHz", "8 kHz", "16 kHz"}
+
+	params := make([]effects.Param, 0, len(names)+1)
+	for _, name := range names {
+		params = append(params, effects.Param{Name: name, Min: -12, Max: 12, Default: 0, Step: 1, Unit: "dB"})
+	}
+	return append(params, effects.Param{Name: "level", Min: -24, Max: 12, Default: 0, Step: 0.5, Unit: "dB"})
+}()
+
+var graphicEQSpec = eqSpec{
+	design:     designGraphicEQ,
+	maxFilters: len(graphicEQBands),
+	level:      len(graphicEQBands),
+}
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:     "graphic eq",
+		Params:   graphicEQParams,
+		Factory:  newGraphicEQ,
+		Response: graphicEQSpec.response,
+	})
+}
+
+func newGraphicEQ(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	return newEQ(cfg, graphicEQSpec), nil
+}
+
+func designGraphicEQ(params *effects.ParamValues, sampleRate float64, filters []dsp.Biquad) {
+	for band, hz := range graphicEQBands {
+		if hz >= 0.45*sampleRate {
+			filters[band].SetPassthrough()
+			continue
+		}
+		filters[band].SetPeaking(hz, graphicEQQ, params.Get(band), sampleRate)
+	}
+}
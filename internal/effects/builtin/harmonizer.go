@@ -0,0 +1,139 @@
+package builtin
+
+import (
+	"math"
+
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const (
+	harmMode = iota
+	harmInterval
+	harmKey
+	harmScale
+	harmDegree
+	harmMix
+)
+
+const (
+	harmChromatic = iota
+	harmDiatonic
+)
+
+var (
+	harmKeyLabels = []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+
+	harmDegreeLabels = []string{
+		"-8va", "-7th", "-6th", "-5th", "-4th", "-3rd", "-2nd",
+		"unison", "2nd", "3rd", "4th", "5th", "6th", "7th", "8va",
+	}
+
+	harmScales = [][]int{
+		{0, 2, 4, 5, 7, 9, 11},
+		{0, 2, 3, 5, 7, 8, 10},
+	}
+)
+
+var harmonizerParams = []effects.Param{
+	{Name: "mode", Min: 0, Max: 1, Default: harmChromatic, Step: 1, Labels: []string{"chromatic", "diatonic"}},
+	{Name: "interval", Min: -12, Max: 12, Default: 7, Step: 1, Unit: "st"},
+	{Name: "key", Min: 0, Max: 11, Default: 0, Step: 1, Labels: harmKeyLabels},
+	{Name: "scale", Min: 0, Max: 1, Default: 0, Step: 1, Labels: []string{"major", "minor"}},
+	{Name: "degree", Min: -7, Max: 7, Default: 2, Step: 1, Labels: harmDegreeLabels},
+	{Name: "mix", Min: 0, Max: 100, Default: 50, Step: 5, Unit: "%"},
+}
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:    "harmonizer",
+		Params:  harmonizerParams,
+		Factory: newHarmonizer,
+	})
+}
+
+type harmonizer struct {
+	pitchEngine
+	params   *effects.ParamValues
+	shifters [dsp.MaxChannels]*dsp.PitchShifter
+	shift    float64
+	last     harmTarget
+}
+
+type harmTarget struct {
+	hz     float64
+	key    int
+	scale  int
+	degree int
+}
+
+func newHarmonizer(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	h := &harmonizer{
+		pitchEngine: newPitchEngine(float64(cfg.SampleRate)),
+		params:      cfg.Params,
+	}
+	for ch := range h.shifters {
+		h.shifters[ch] = h.newShifter()
+	}
+	return h, nil
+}
+
+func diatonicShift(hz float64, key, scale, steps int) float64 {
+	degrees := harmScales[scale]
+
+	note := int(math.Round(69 + 12*math.Log2(hz/440)))
+	pc := ((note-key)%12 + 12) % 12
+
+	idx := 0
+	for i, d := range degrees {
+		if d <= pc {
+			idx = i
+		}
+	}
+
+	target := idx + steps
+	octaves := int(math.Floor(float64(target) / float64(len(degrees))))
+	target -= octaves * len(degrees)
+
+	return float64(octaves*12 + degrees[target] - pc)
+}
+
+func (h *harmonizer) Process(_ *effects.ProcessContext, channels [][]float32) {
+	diatonic := h.params.Get(harmMode) >= 0.5
+	interval := h.params.Get(harmInterval)
+	key := int(h.params.Get(harmKey))
+	scale := int(h.params.Get(harmScale))
+	degree := int(h.params.Get(harmDegree))
+	wet := float32(h.params.Get(harmMix) / 100)
+
+	if !diatonic {
+		h.shift = interval
+		h.last = harmTarget{}
+	}
+
+	for i := range channels[0] {
+		h.analyze(channels, i)
+		grain := h.grain()
+
+		if diatonic {
+			if hz, voiced := h.detector.Pitch(); voiced {
+				if target := (harmTarget{hz: hz, key: key, scale: scale, degree: degree}); target != h.last {
+					h.shift = diatonicShift(hz, key, scale, degree)
+					h.last = target
+				}
+			}
+		}
+		ratio := semitoneRatio(h.shift)
+
+		for ch, samples := range channels {
+			h.shifters[ch].SetGrain(grain)
+
+			x := samples[i]
+			samples[i] = x*(1-wet) + h.shifters[ch].Process(x, ratio)*wet
+		}
+	}
+}
+
+func (h *harmonizer) LatencyFrames() int {
+	return h.latencyFrames()
+}
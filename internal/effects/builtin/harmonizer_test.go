@@ -0,0 +1,103 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+func sustainedNote() []float32 {
+	period := sine(375, 0.5)[:testSampleRate/375]
+	samples := make([]float32, testSampleRate)
+	for i := range samples {
+		samples[i] = period[i%len(period)]
+	}
+	return samples
+}
+
+func TestHarmonizer(t *testing.T) {
+	t.Run("diatonicShift", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			hz    float64
+			key   int
+			scale int
+			steps int
+			want  float64
+		}{
+			{name: "should harmonize C a major third up in C major", hz: 261.63, key: 0, scale: 0, steps: 2, want: 4},
+			{name: "should harmonize E a minor third up in C major", hz: 329.63, key: 0, scale: 0, steps: 2, want: 3},
+			{name: "should harmonize A a minor third up in A minor", hz: 440, key: 9, scale: 1, steps: 2, want: 3},
+			{name: "should harmonize G a fourth down in C major", hz: 392, key: 0, scale: 0, steps: -3, want: -5},
+			{name: "should wrap G a sixth up across the octave", hz: 392, key: 0, scale: 0, steps: 5, want: 9},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got := diatonicShift(tt.hz, tt.key, tt.scale, tt.steps)
+
+				if got != tt.want {
+					t.Errorf("got %v semitones, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("Process", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			param string
+			value float64
+			want  float64
+		}{
+			{name: "should follow a degree change on a sustained note", param: "degree", value: 4, want: 6},
+			{name: "should follow a key change on a sustained note", param: "key", value: 6, want: 4},
+			{name: "should follow a scale change on a sustained note", param: "scale", value: 1, want: 2},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := newTestBuiltin(t, "harmonizer", map[string]float64{"mode": harmDiatonic, "key": 0, "degree": 2}).(*harmonizer)
+				note := sustainedNote()
+				half := len(note) / 2
+				sut.Process(nil, [][]float32{note[:half]})
+
+				sut.params.Set(tt.param, tt.value)
+				sut.Process(nil, [][]float32{note[half:]})
+
+				if sut.shift != tt.want {
+					t.Errorf("got %v semitones, want %v", sut.shift, tt.want)
+				}
+			})
+		}
+
+		t.Run("should recompute the diatonic shift after a chromatic detour", func(t *testing.T) {
+			sut := newTestBuiltin(t, "harmonizer", map[string]float64{"mode": harmDiatonic, "key": 0, "degree": 2}).(*harmonizer)
+			note := sustainedNote()
+			third := len(note) / 3
+			sut.Process(nil, [][]float32{note[:third]})
+
+			sut.params.Set("mode", harmChromatic)
+			sut.Process(nil, [][]float32{note[third : 2*third]})
+			sut.params.Set("mode", harmDiatonic)
+			sut.Process(nil, [][]float32{note[2*third:]})
+
+			if sut.shift != 3 {
+				t.Errorf("got %v semitones, want 3", sut.shift)
+			}
+		})
+	})
+
+	t.Run("LatencyFrames", func(t *testing.T) {
+		t.Run("should cover the widest grain and the low-note detection window", func(t *testing.T) {
+			sut := newTestBuiltin(t, "harmonizer", nil)
+
+			got := sut.(effects.LatencyReporter).LatencyFrames()
+
+			want := shiftMaxGrainMs*testSampleRate/1000/2 + 2*testSampleRate/pitchMinHz
+			if got < want {
+				t.Errorf("got %d latency frames, want at least %d", got, want)
+			}
+		})
+	})
+}
@@ -0,0 +1,120 @@
+package builtin
+
+import (
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const (
+	lfoRate = iota
+	lfoSync
+	lfoReset
+	lfoParamCount
+)
+
+const (
+	lfoSine = iota
+	lfoTriangle
+	lfoSquare
+)
+
+var lfoShapeLabels = []string{"sine", "triangle", "square"}
+
+func lfoParams(defaultHz, maxHz float64, extra ...effects.Param) []effects.Param {
+	return append([]effects.Param{
+		{Name: "rate", Min: 0.05, Max: maxHz, Default: defaultHz, Step: 0.05, Unit: "Hz"},
+		{Name: "sync", Min: 0, Max: float64(len(noteValues)), Default: 0, Step: 1, Labels: append([]string{"free"}, noteLabels()...)},
+		{Name: "onset reset", Min: 0, Max: 1, Default: 0, Step: 1, Labels: []string{"off", "on"}},
+	}, extra...)
+}
+
+type modulator struct {
+	sampleRate float64
+	params     *effects.ParamValues
+	lfo        dsp.LFO
+	hz         float64
+}
+
+func (m *modulator) begin(ctx *effects.ProcessContext) {
+	if ctx.Onset && m.params.Get(lfoReset) >= 0.5 {
+		m.lfo.Reset()
+	}
+
+	m.hz = m.params.Get(lfoRate)
+	if sync := m.params.Get(lfoSync); sync >= 1 {
+		m.hz = 1 / noteSeconds(sync-1, ctx.BPM)
+	}
+}
+
+func (m *modulator) advance() {
+	m.lfo.Advance(m.hz, m.sampleRate)
+}
+
+func (m *modulator) shape(shape int, offset float64) float32 {
+	switch shape {
+	case lfoTriangle:
+		return m.lfo.Triangle(offset)
+	case lfoSquare:
+		return m.lfo.Square(offset)
+	default:
+		return m.lfo.Sine(offset)
+	}
+}
+
+type modDelaySpec struct {
+	baseMs      float64
+	maxDepthMs  float64
+	stereoPhase float64
+	feedback    int
+	mix         int
+}
+
+const modDepth = lfoParamCount
+
+type modDelay struct {
+	modulator
+	spec  modDelaySpec
+	lines [dsp.MaxChannels]*dsp.DelayLine
+}
+
+func newModDelay(cfg effects.BuiltinConfig, spec modDelaySpec) *modDelay {
+	d := &modDelay{
+		modulator: modulator{sampleRate: float64(cfg.SampleRate), params: cfg.Params},
+		spec:      spec,
+	}
+	maxSamples := int((spec.baseMs + spec.maxDepthMs) * d.sampleRate / 1000)
+	for ch := range d.lines {
+		d.lines[ch] = dsp.NewDelayLine(maxSamples + 2)
+	}
+	return d
+}
+
+func (d *modDelay) param(i int, fallback float64) float64 {
+	if i < 0 {
+		return fallback
+	}
+	return d.params.Get(i)
+}
+
+func (d *modDelay) Process(ctx *effects.ProcessContext, channels [][]float32) {
+	d.begin(ctx)
+
+	msToSamples := d.sampleRate / 1000
+	base := d.spec.baseMs * msToSamples
+	depth := d.params.Get(modDepth) * msToSamples
+	fb := float32(d.param(d.spec.feedback, 0) / 100)
+	wet := float32(d.param(d.spec.mix, 100) / 100)
+
+	for i := range channels[0] {
+		for ch, samples := range channels {
+			mod := 0.5 * (1 + float64(d.lfo.Sine(d.spec.stereoPhase*float64(ch))))
+			x := samples[i]
+			y := d.lines[ch].Read(base + depth*mod)
+
+			d.lines[ch].Write(x + fb*y)
+
+			samples[i] = x*(1-wet) + y*wet
+		}
+		d.advance()
+	}
+}
@@ -0,0 +1,156 @@
+package builtin
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+func TestTremolo(t *testing.T) {
+	quarter := float64(slices.IndexFunc(noteValues, func(n noteValue) bool { return n.label == "1/4" }) + 1)
+
+	t.Run("Process", func(t *testing.T) {
+		t.Run("should sync LFO rate to the tempo subdivision", func(t *testing.T) {
+			sut := newTestBuiltin(t, "tremolo", map[string]float64{"depth": 100, "shape": lfoSquare, "sync": quarter})
+			got := constant(testSampleRate, 1)
+
+			sut.Process(&effects.ProcessContext{SampleRate: testSampleRate, BPM: 120}, [][]float32{got})
+
+			for _, ms := range []int{100, 600} {
+				if i := ms * testSampleRate / 1000; got[i] != 0 {
+					t.Errorf("got %v at %d ms, want muted first half of the cycle", got[i], ms)
+				}
+			}
+			for _, ms := range []int{300, 800} {
+				if i := ms * testSampleRate / 1000; got[i] != 1 {
+					t.Errorf("got %v at %d ms, want open second half of the cycle", got[i], ms)
+				}
+			}
+		})
+
+		t.Run("should reset LFO phase on onset", func(t *testing.T) {
+			sut := newTestBuiltin(t, "tremolo", map[string]float64{"depth": 100, "onset reset": 1})
+			sut.Process(&effects.ProcessContext{SampleRate: testSampleRate}, [][]float32{constant(123, 1)})
+			got := constant(1, 1)
+
+			sut.Process(&effects.ProcessContext{SampleRate: testSampleRate, Onset: true}, [][]float32{got})
+
+			if got[0] != 0.5 {
+				t.Errorf("got %v, want LFO restarted at zero phase", got[0])
+			}
+		})
+
+		t.Run("should swing the right channel opposite to the left in stereo mode", func(t *testing.T) {
+			sut := newTestBuiltin(t, "tremolo", map[string]float64{"depth": 100, "shape": lfoSquare, "stereo": 1})
+			left, right := constant(testFrames, 1), constant(testFrames, 1)
+
+			sut.Process(&effects.ProcessContext{SampleRate: testSampleRate}, [][]float32{left, right})
+
+			if left[0] != 0 || right[0] != 1 {
+				t.Errorf("got left %v right %v, want 0 and 1", left[0], right[0])
+			}
+		})
+	})
+}
+
+func TestChorus(t *testing.T) {
+	t.Run("Process", func(t *testing.T) {
+		t.Run("should delay the wet signal by the base time at zero depth", func(t *testing.T) {
+			sut := newTestBuiltin(t, "chorus", map[string]float64{"depth": 0, "mix": 50})
+			got := impulse(testSampleRate / 10)
+
+			sut.Process(&effects.ProcessContext{SampleRate: testSampleRate}, [][]float32{got})
+
+			want := 7 * testSampleRate / 1000
+			if got[0] != 0.5 || got[want] != 0.5 {
+				t.Errorf("got dry %v and wet %v at %d, want 0.5 each", got[0], got[want], want)
+			}
+		})
+
+		t.Run("should offset the right channel's sweep by a quarter cycle", func(t *testing.T) {
+			sut := newTestBuiltin(t, "chorus", map[string]float64{"rate": 0.05, "depth": 10, "mix": 100})
+			left, right := impulse(testSampleRate/10), impulse(testSampleRate/10)
+
+			sut.Process(&effects.ProcessContext{SampleRate: testSampleRate}, [][]float32{left, right})
+
+			if got, want := peakIndex(left), 12*testSampleRate/1000; abs(float32(got-want)) > 2 {
+				t.Errorf("got left echo at %d, want about %d", got, want)
+			}
+			if got, want := peakIndex(right), 17*testSampleRate/1000; abs(float32(got-want)) > 2 {
+				t.Errorf("got right echo at %d, want about %d", got, want)
+			}
+		})
+	})
+}
+
+func TestFlanger(t *testing.T) {
+	t.Run("Process", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			feedback float64
+			want     float32
+		}{
+			{name: "should regenerate repeats with positive feedback", feedback: 50, want: 0.5},
+			{name: "should invert repeats with negative feedback", feedback: -50, want: -0.5},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := newTestBuiltin(t, "flanger", map[string]float64{"depth": 0, "feedback": tt.feedback, "mix": 100})
+				got := impulse(testFrames)
+
+				sut.Process(&effects.ProcessContext{SampleRate: testSampleRate}, [][]float32{got})
+
+				base := testSampleRate / 2000
+				if got[base] != 1 || got[2*base] != tt.want {
+					t.Errorf("got %v then %v, want 1 then %v", got[base], got[2*base], tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestVibrato(t *testing.T) {
+	t.Run("Process", func(t *testing.T) {
+		t.Run("should output only the modulated delay centred at zero phase", func(t *testing.T) {
+			sut := newTestBuiltin(t, "vibrato", map[string]float64{"rate": 0.05, "depth": 1})
+			got := impulse(testFrames)
+
+			sut.Process(&effects.ProcessContext{SampleRate: testSampleRate}, [][]float32{got})
+
+			if got[0] != 0 {
+				t.Errorf("got %v at 0, want no dry signal", got[0])
+			}
+			if got, want := peakIndex(got), 3*testSampleRate/2000; got != want {
+				t.Errorf("got peak at %d, want %d", got, want)
+			}
+		})
+	})
+}
+
+func TestPhaser(t *testing.T) {
+	t.Run("Process", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			hz    float64
+			check func(db float64) bool
+		}{
+			{name: "should notch where two stages shift by 180 degrees", hz: phaserMinHz, check: func(db float64) bool { return db < -40 }},
+			{name: "should pass frequencies far above the notch", hz: 4000, check: func(db float64) bool { return db > -4 }},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := newTestBuiltin(t, "phaser", map[string]float64{"stages": 0, "depth": 0, "feedback": 0, "mix": 50})
+				got := sine(tt.hz, 1)
+
+				sut.Process(&effects.ProcessContext{SampleRate: testSampleRate}, [][]float32{got})
+
+				if db := rmsDb(got[testSampleRate/2:]); !tt.check(db) {
+					t.Errorf("got %.1f dB RMS at %.0f Hz", db, tt.hz)
+				}
+			})
+		}
+	})
+}
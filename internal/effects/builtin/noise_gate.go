@@ -0,0 +1,132 @@
+package builtin
+
+import (
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const (
+	gateThreshold = iota
+	gateHysteresis
+	gateAttack
+	gateHold
+	gateRelease
+)
+
+const (
+	gateKeyAttackMs = 0.1
+
+	gateKeyReleaseMs = 10
+
+	maxReductionDb = 96
+)
+
+var noiseGateParams = []effects.Param{
+	{Name: "threshold", Min: -90, Max: 0, Default: -50, Step: 1, Unit: "dB"},
+	{Name: "hysteresis", Min: 0, Max: 20, Default: 6, Step: 1, Unit: "dB"},
+	{Name: "attack", Min: 0.1, Max: 50, Default: 1, Step: 0.5, Unit: "ms"},
+	{Name: "hold", Min: 0, Max: 500, Default: 50, Step: 5, Unit: "ms"},
+	{Name: "release", Min: 5, Max: 2000, Default: 150, Step: 5, Unit: "ms"},
+}
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:    "noise gate",
+		Params:  noiseGateParams,
+		Factory: newNoiseGate,
+	})
+}
+
+type noiseGate struct {
+	sampleRate float64
+	params     *effects.ParamValues
+
+	key      dsp.Envelope
+	open     bool
+	holdLeft int
+	gain     float32
+
+	attackMs     float64
+	releaseMs    float64
+	attackCoeff  float32
+	releaseCoeff float32
+
+	reduction effects.Meter
+}
+
+func newNoiseGate(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	g := &noiseGate{sampleRate: float64(cfg.SampleRate), params: cfg.Params}
+	g.key.SetTimes(gateKeyAttackMs, gateKeyReleaseMs, g.sampleRate)
+	return g, nil
+}
+
+func (g *noiseGate) updateCoeffs() {
+	attack, release := g.params.Get(gateAttack), g.params.Get(gateRelease)
+	if attack == g.attackMs && release == g.releaseMs {
+		return
+	}
+	g.attackMs, g.releaseMs = attack, release
+	g.attackCoeff = dsp.SmoothingCoeff(attack, g.sampleRate)
+	g.releaseCoeff = dsp.SmoothingCoeff(release, g.sampleRate)
+}
+
+func (g *noiseGate) Process(ctx *effects.ProcessContext, channels [][]float32) {
+	g.updateCoeffs()
+
+	threshold := g.params.Get(gateThreshold)
+	openLevel := float32(dsp.DbToLinear(threshold))
+	closeLevel := float32(dsp.DbToLinear(threshold - g.params.Get(gateHysteresis)))
+	holdFrames := int(g.params.Get(gateHold) * g.sampleRate / 1000)
+
+	key := ctx.Dry
+	if len(key) == 0 {
+		key = channels
+	}
+
+	for i := range channels[0] {
+		var peak float32
+		for _, ch := range key {
+			if i < len(ch) {
+				peak = max(peak, abs(ch[i]))
+			}
+		}
+		level := g.key.Process(peak)
+
+		switch {
+		case level >= openLevel:
+			g.open = true
+			g.holdLeft = holdFrames
+		case level >= closeLevel:
+			if g.open {
+				g.holdLeft = holdFrames
+			}
+		case g.holdLeft > 0:
+			g.holdLeft--
+		default:
+			g.open = false
+		}
+
+		target, coeff := float32(0), g.releaseCoeff
+		if g.open {
+			target, coeff = 1, g.attackCoeff
+		}
+		g.gain += coeff * (target - g.gain)
+
+		for _, ch := range channels {
+			ch[i] *= g.gain
+		}
+	}
+
+	g.reduction.Store(min(-dsp.LinearToDb(float64(g.gain)), maxReductionDb))
+}
+
+func (g *noiseGate) GainReductionDb() float64 {
+	return g.reduction.Load()
+}
+
+func abs(x float32) float32 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
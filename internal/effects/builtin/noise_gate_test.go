@@ -0,0 +1,54 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+func runGate(sut effects.Processor, dry, wet float32, blocks int) []float32 {
+	var out []float32
+	for range blocks {
+		out = constant(testFrames, wet)
+		ctx := &effects.ProcessContext{SampleRate: testSampleRate, Dry: [][]float32{constant(testFrames, dry)}}
+		sut.Process(ctx, [][]float32{out})
+	}
+	return out
+}
+
+func TestNoiseGate(t *testing.T) {
+	t.Run("Process", func(t *testing.T) {
+		t.Run("should close when dry input is below threshold", func(t *testing.T) {
+			sut := newTestBuiltin(t, "noise gate", nil)
+
+			got := runGate(sut, 0.001, 0.5, 200)
+
+			if got[testFrames-1] > 0.001 {
+				t.Errorf("got %v, want gated output", got[testFrames-1])
+			}
+			if gr := sut.(effects.GainReducer).GainReductionDb(); gr < 40 {
+				t.Errorf("got gain reduction %.1f dB, want > 40", gr)
+			}
+		})
+
+		t.Run("should open from dry input even when processed signal is quiet", func(t *testing.T) {
+			sut := newTestBuiltin(t, "noise gate", nil)
+
+			got := runGate(sut, 0.5, 0.001, 20)
+
+			if got[testFrames-1] < 0.00099 {
+				t.Errorf("got %v, want 0.001 passed through", got[testFrames-1])
+			}
+		})
+
+		t.Run("should respect threshold override", func(t *testing.T) {
+			sut := newTestBuiltin(t, "noise gate", map[string]float64{"threshold": -70})
+
+			got := runGate(sut, 0.001, 0.5, 20)
+
+			if got[testFrames-1] < 0.49 {
+				t.Errorf("got %v, want open gate", got[testFrames-1])
+			}
+		})
+	})
+}
@@ -0,0 +1,68 @@
+package builtin
+
+import (
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const (
+	octaveUp = iota
+	octaveDown
+	octaveDry
+)
+
+var octaveParams = []effects.Param{
+	{Name: "up", Min: 0, Max: 100, Default: 0, Step: 5, Unit: "%"},
+	{Name: "down", Min: 0, Max: 100, Default: 60, Step: 5, Unit: "%"},
+	{Name: "dry", Min: 0, Max: 100, Default: 100, Step: 5, Unit: "%"},
+}
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:    "octave",
+		Params:  octaveParams,
+		Factory: newOctave,
+	})
+}
+
+type octave struct {
+	pitchEngine
+	params *effects.ParamValues
+	up     [dsp.MaxChannels]*dsp.PitchShifter
+	down   [dsp.MaxChannels]*dsp.PitchShifter
+}
+
+func newOctave(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	o := &octave{
+		pitchEngine: newPitchEngine(float64(cfg.SampleRate)),
+		params:      cfg.Params,
+	}
+	for ch := range o.up {
+		o.up[ch] = o.newShifter()
+		o.down[ch] = o.newShifter()
+	}
+	return o, nil
+}
+
+func (o *octave) Process(_ *effects.ProcessContext, channels [][]float32) {
+	up := float32(o.params.Get(octaveUp) / 100)
+	down := float32(o.params.Get(octaveDown) / 100)
+	dry := float32(o.params.Get(octaveDry) / 100)
+
+	for i := range channels[0] {
+		o.analyze(channels, i)
+		grain := o.grain()
+
+		for ch, samples := range channels {
+			o.up[ch].SetGrain(grain)
+			o.down[ch].SetGrain(grain)
+
+			x := samples[i]
+			samples[i] = x*dry + o.up[ch].Process(x, 2)*up + o.down[ch].Process(x, 0.5)*down
+		}
+	}
+}
+
+func (o *octave) LatencyFrames() int {
+	return o.latencyFrames()
+}
@@ -0,0 +1,113 @@
+package builtin
+
+import (
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const (
+	peqHighpassSlope = iota
+	peqHighpassFreq
+	peqLowShelfFreq
+	peqLowShelfGain
+	peqPeak1Freq
+	peqPeak1Gain
+	peqPeak1Q
+	peqPeak2Freq
+	peqPeak2Gain
+	peqPeak2Q
+	peqPeak3Freq
+	peqPeak3Gain
+	peqPeak3Q
+	peqHighShelfFreq
+	peqHighShelfGain
+	peqLowpassSlope
+	peqLowpassFreq
+	peqLevel
+)
+
+const (
+	peqPeaks = 3
+
+	peqMaxSections = 4
+
+	peqMaxFilters = 2*peqMaxSections + 2 + peqPeaks
+)
+
+var peqSlopeLabels = []string{"off", "12 dB/oct", "24 dB/oct", "36 dB/oct", "48 dB/oct"}
+
+func peqPeakParams(n string, freq float64) []effects.Param {
+	return []effects.Param{
+		{Name: "peak " + n + " freq", Min: 20, Max: 20000, Default: freq, Step: 1.0 / 6, Unit: "Hz", Log: true},
+		{Name: "peak " + n + " gain", Min: -18, Max: 18, Default: 0, Step: 0.5, Unit: "dB"},
+		{Name: "peak " + n + " q", Min: 0.1, Max: 10, Default: 1, Step: 0.1},
+	}
+}
+
+var parametricEQParams = func() []effects.Param {
+	params := []effects.Param{
+		{Name: "hp slope", Min: 0, Max: peqMaxSections, Default: 0, Step: 1, Labels: peqSlopeLabels},
+		{Name: "hp freq", Min: 20, Max: 1000, Default: 80, Step: 1.0 / 6, Unit: "Hz", Log: true},
+		{Name: "low freq", Min: 20, Max: 1000, Default: 120, Step: 1.0 / 6, Unit: "Hz", Log: true},
+		{Name: "low gain", Min: -18, Max: 18, Default: 0, Step: 0.5, Unit: "dB"},
+	}
+	params = append(params, peqPeakParams("1", 400)...)
+	params = append(params, peqPeakParams("2", 1200)...)
+	params = append(params, peqPeakParams("3", 3000)...)
+	return append(params,
+		effects.Param{Name: "high freq", Min: 1000, Max: 16000, Default: 6000, Step: 1.0 / 6, Unit: "Hz", Log: true},
+		effects.Param{Name: "high gain", Min: -18, Max: 18, Default: 0, Step: 0.5, Unit: "dB"},
+		effects.Param{Name: "lp slope", Min: 0, Max: peqMaxSections, Default: 0, Step: 1, Labels: peqSlopeLabels},
+		effects.Param{Name: "lp freq", Min: 1000, Max: 20000, Default: 8000, Step: 1.0 / 6, Unit: "Hz", Log: true},
+		effects.Param{Name: "level", Min: -24, Max: 12, Default: 0, Step: 0.5, Unit: "dB"},
+	)
+}()
+
+var parametricEQSpec = eqSpec{
+	design:     designParametricEQ,
+	maxFilters: peqMaxFilters,
+	level:      peqLevel,
+}
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:     "parametric eq",
+		Params:   parametricEQParams,
+		Factory:  newParametricEQ,
+		Response: parametricEQSpec.response,
+	})
+}
+
+func newParametricEQ(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	return newEQ(cfg, parametricEQSpec), nil
+}
+
+func designParametricEQ(params *effects.ParamValues, sampleRate float64, filters []dsp.Biquad) {
+	designSlope(filters[:peqMaxSections], int(params.Get(peqHighpassSlope)), func(f *dsp.Biquad, q float64) {
+		f.SetHighpass(params.Get(peqHighpassFreq), q, sampleRate)
+	})
+
+	shelves := filters[peqMaxSections:]
+	shelves[0].SetLowShelf(params.Get(peqLowShelfFreq), params.Get(peqLowShelfGain), sampleRate)
+
+	for p := 0; p < peqPeaks; p++ {
+		base := peqPeak1Freq + 3*p
+		shelves[1+p].SetPeaking(params.Get(base), params.Get(base+2), params.Get(base+1), sampleRate)
+	}
+
+	shelves[1+peqPeaks].SetHighShelf(params.Get(peqHighShelfFreq), params.Get(peqHighShelfGain), sampleRate)
+
+	designSlope(filters[peqMaxFilters-peqMaxSections:], int(params.Get(peqLowpassSlope)), func(f *dsp.Biquad, q float64) {
+		f.SetLowpass(params.Get(peqLowpassFreq), q, sampleRate)
+	})
+}
+
+func designSlope(filters []dsp.Biquad, sections int, set func(f *dsp.Biquad, q float64)) {
+	for s := range filters {
+		if s < sections {
+			set(&filters[s], butterworthQ(2*sections, s))
+		} else {
+			filters[s].SetPassthrough()
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package builtin
+
+import (
+	"math"
+
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const (
+	phaserStages = lfoParamCount + iota
+	phaserDepth
+	phaserFeedback
+	phaserMix
+)
+
+const (
+	phaserMinHz = 200
+
+	phaserMaxHz = 2400
+
+	phaserMaxStages = 8
+)
+
+var phaserParams = lfoParams(0.5, 5,
+	effects.Param{Name: "stages", Min: 0, Max: 2, Default: 1, Step: 1, Labels: []string{"2", "4", "8"}},
+	effects.Param{Name: "depth", Min: 0, Max: 100, Default: 80, Step: 5, Unit: "%"},
+	effects.Param{Name: "feedback", Min: 0, Max: 90, Default: 40, Step: 5, Unit: "%"},
+	effects.Param{Name: "mix", Min: 0, Max: 100, Default: 50, Step: 5, Unit: "%"},
+)
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:    "phaser",
+		Params:  phaserParams,
+		Factory: newPhaser,
+	})
+}
+
+type allpassStage struct {
+	x1 float32
+	y1 float32
+}
+
+type phaser struct {
+	modulator
+	stages [dsp.MaxChannels][phaserMaxStages]allpassStage
+	last   [dsp.MaxChannels]float32
+}
+
+func newPhaser(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	return &phaser{modulator: modulator{sampleRate: float64(cfg.SampleRate), params: cfg.Params}}, nil
+}
+
+func (p *phaser) coefficient(hz float64) float32 {
+	t := math.Tan(math.Pi * min(hz, 0.45*p.sampleRate) / p.sampleRate)
+	return float32((t - 1) / (t + 1))
+}
+
+func (p *phaser) Process(ctx *effects.ProcessContext, channels [][]float32) {
+	p.begin(ctx)
+
+	count := 2 << int(p.params.Get(phaserStages))
+	depth := p.params.Get(phaserDepth) / 100
+	fb := float32(p.params.Get(phaserFeedback) / 100)
+	wet := float32(p.params.Get(phaserMix) / 100)
+	span := math.Log(phaserMaxHz / phaserMinHz)
+
+	for i := range channels[0] {
+		for ch, samples := range channels {
+			mod := 0.5 * (1 + float64(p.lfo.Sine(0.25*float64(ch))))
+			a := p.coefficient(phaserMinHz * math.Exp(span*depth*mod))
+
+			x := samples[i]
+			y := x + fb*p.last[ch]
+			for s := range p.stages[ch][:count] {
+				stage := &p.stages[ch][s]
+				out := a*y + stage.x1 - a*stage.y1
+				stage.x1, stage.y1 = y, out
+				y = out
+			}
+			p.last[ch] = y
+
+			samples[i] = x*(1-wet) + y*wet
+		}
+		p.advance()
+	}
+}
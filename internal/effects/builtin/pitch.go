@@ -0,0 +1,59 @@
+package builtin
+
+import (
+	"math"
+
+	"github.com/chloyka/gorig/internal/dsp"
+)
+
+const (
+	pitchMinHz = 70
+
+	pitchMaxHz = 1200
+
+	shiftGrainMs = 25
+
+	shiftMaxGrainMs = 45
+)
+
+type pitchEngine struct {
+	sampleRate float64
+	detector   *dsp.PitchDetector
+}
+
+func newPitchEngine(sampleRate float64) pitchEngine {
+	return pitchEngine{
+		sampleRate: sampleRate,
+		detector:   dsp.NewPitchDetector(sampleRate, pitchMinHz, pitchMaxHz),
+	}
+}
+
+func (e *pitchEngine) newShifter() *dsp.PitchShifter {
+	return dsp.NewPitchShifter(int(shiftMaxGrainMs * e.sampleRate / 1000))
+}
+
+func (e *pitchEngine) analyze(channels [][]float32, i int) {
+	var mono float32
+	for _, ch := range channels {
+		mono += ch[i]
+	}
+	e.detector.Push(mono / float32(len(channels)))
+}
+
+func (e *pitchEngine) grain() float64 {
+	nominal := shiftGrainMs * e.sampleRate / 1000
+	hz, voiced := e.detector.Pitch()
+	if !voiced {
+		return nominal
+	}
+	period := e.sampleRate / hz
+	return period * math.Ceil(nominal/period)
+}
+
+func (e *pitchEngine) latencyFrames() int {
+	return int(shiftMaxGrainMs/2*e.sampleRate/1000) + e.detector.LatencyFrames()
+}
+
+func semitoneRatio(semitones float64) float64 {
+	return math.Exp2(semitones / 12)
+}
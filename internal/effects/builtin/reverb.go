@@ -0,0 +1,156 @@
+package builtin
+
+import (
+	"math"
+
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const (
+	reverbSize = iota
+	reverbDecay
+	reverbPreDelay
+	reverbDamping
+	reverbMix
+)
+
+const (
+	reverbLines = 8
+
+	reverbMaxPreDelayMs = 200
+
+	reverbSizeGlideMs = 100
+
+	reverbInputGain = 0.8
+)
+
+var (
+	reverbLineMs = [reverbLines]float64{29.7, 37.1, 41.1, 43.7, 53.3, 59.9, 67.7, 79.3}
+
+	reverbDiffuserMs = []float64{4.77, 3.59, 12.73, 9.31}
+
+	reverbDiffuserGain = []float32{0.75, 0.75, 0.625, 0.625}
+)
+
+var reverbParams = []effects.Param{
+	{Name: "size", Min: 10, Max: 100, Default: 60, Step: 5, Unit: "%"},
+	{Name: "decay", Min: 0.2, Max: 10, Default: 2, Step: 0.1, Unit: "s"},
+	{Name: "pre-delay", Min: 0, Max: reverbMaxPreDelayMs, Default: 20, Step: 5, Unit: "ms"},
+	{Name: "damping", Min: 0, Max: 100, Default: 40, Step: 5, Unit: "%"},
+	{Name: "mix", Min: 0, Max: 100, Default: 25, Step: 5, Unit: "%"},
+}
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:    "reverb",
+		Params:  reverbParams,
+		Factory: newReverb,
+	})
+}
+
+type reverb struct {
+	sampleRate float64
+	params     *effects.ParamValues
+
+	preDelay  *dsp.DelayLine
+	diffusers []*dsp.Allpass
+	lines     [reverbLines]*dsp.DelayLine
+	damping   [reverbLines]dsp.OnePole
+	gains     [reverbLines]float32
+
+	size      float64
+	sizeGlide float64
+	dampingHz float64
+}
+
+func newReverb(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	r := &reverb{
+		sampleRate: float64(cfg.SampleRate),
+		params:     cfg.Params,
+	}
+	r.sizeGlide = float64(dsp.SmoothingCoeff(reverbSizeGlideMs, r.sampleRate))
+	r.preDelay = dsp.NewDelayLine(int(reverbMaxPreDelayMs * r.sampleRate / 1000))
+
+	for i, ms := range reverbDiffuserMs {
+		r.diffusers = append(r.diffusers, dsp.NewAllpass(int(ms*r.sampleRate/1000), reverbDiffuserGain[i]))
+	}
+	for i, ms := range reverbLineMs {
+		r.lines[i] = dsp.NewDelayLine(int(ms * r.sampleRate / 1000))
+	}
+	return r, nil
+}
+
+func (r *reverb) updateDamping() {
+	damping := r.params.Get(reverbDamping) / 100
+	hz := 16000 * math.Pow(0.05, damping)
+	if hz == r.dampingHz {
+		return
+	}
+	r.dampingHz = hz
+	for i := range r.damping {
+		r.damping[i].SetCutoff(hz, r.sampleRate)
+	}
+}
+
+func (r *reverb) updateGains(decay float64) {
+	for i, ms := range reverbLineMs {
+		seconds := ms * r.size / 1000
+		r.gains[i] = float32(math.Pow(10, -3*seconds/decay))
+	}
+}
+
+func (r *reverb) Process(_ *effects.ProcessContext, channels [][]float32) {
+	r.updateDamping()
+
+	targetSize := r.params.Get(reverbSize) / 100
+	if r.size == 0 {
+		r.size = targetSize
+	}
+	r.updateGains(r.params.Get(reverbDecay))
+
+	preDelay := max(1, r.params.Get(reverbPreDelay)*r.sampleRate/1000)
+	wet := float32(r.params.Get(reverbMix) / 100)
+	inputScale := 1 / float32(len(channels))
+
+	var taps, fb [reverbLines]float32
+
+	for i := range channels[0] {
+		r.size += (targetSize - r.size) * r.sizeGlide
+
+		var in float32
+		for _, ch := range channels {
+			in += ch[i]
+		}
+		in *= inputScale
+
+		r.preDelay.Write(in)
+		x := r.preDelay.Read(preDelay)
+		for _, ap := range r.diffusers {
+			x = ap.Process(x)
+		}
+		x *= reverbInputGain
+
+		var sum float32
+		for l, line := range r.lines {
+			taps[l] = line.Read(reverbLineMs[l] * r.size * r.sampleRate / 1000)
+			fb[l] = r.damping[l].Lowpass(taps[l]) * r.gains[l]
+			sum += fb[l]
+		}
+		sum *= 2.0 / reverbLines
+
+		for l, line := range r.lines {
+			line.Write(x + fb[l] - sum)
+		}
+
+		left := 0.5 * (taps[0] - taps[2] + taps[4] - taps[6])
+		right := 0.5 * (taps[1] - taps[3] + taps[5] - taps[7])
+
+		if len(channels) == 1 {
+			channels[0][i] = channels[0][i]*(1-wet) + 0.5*(left+right)*wet
+			continue
+		}
+		channels[0][i] = channels[0][i]*(1-wet) + left*wet
+		channels[1][i] = channels[1][i]*(1-wet) + right*wet
+	}
+}
@@ -0,0 +1,139 @@
+package builtin
+
+import (
+	"math"
+	"testing"
+
+	configTypes "github.com/chloyka/gorig/internal/config/types"
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+	"github.com/chloyka/gorig/internal/logger"
+	"go.uber.org/zap"
+)
+
+func rmsDb(samples []float32) float64 {
+	var sum float64
+	for _, s := range samples {
+		sum += float64(s) * float64(s)
+	}
+	return dsp.LinearToDb(math.Sqrt(sum / float64(len(samples))))
+}
+
+const spilloverFrames = 256
+
+type spilloverRig struct {
+	chain   *effects.Chain
+	presets *configTypes.PresetsConfig
+	buf     *dsp.Buffer
+}
+
+func newSpilloverRig(t *testing.T, sampleRate int, spillover bool, decay float64) *spilloverRig {
+	t.Helper()
+
+	presets := &configTypes.PresetsConfig{
+		Presets: []configTypes.Preset{
+			{
+				Name:        "hall",
+				EffectChain: []string{"reverb"},
+				Slots: []configTypes.SlotSettings{{Params: map[string]float64{
+					"decay": decay, "pre-delay": 0, "mix": 100,
+				}}},
+			},
+			{Name: "clean", EffectChain: []string{}},
+		},
+		ActivePreset: "hall",
+	}
+	log := &logger.Logger{Logger: zap.NewNop()}
+	state := &configTypes.StateConfig{EffectsEnabled: true}
+	chain := effects.NewChain(log, t.TempDir(), t.TempDir(), state, presets, effects.TransitionConfig{
+		SampleRate: sampleRate,
+		MaxFrames:  spilloverFrames,
+		FadeFrames: sampleRate / 100,
+		Spillover:  spillover,
+	})
+
+	return &spilloverRig{chain: chain, presets: presets, buf: dsp.NewBuffer(1, spilloverFrames)}
+}
+
+func (r *spilloverRig) switchTo(name string) {
+	r.presets.ActivePreset = name
+	r.chain.SetPresetChain(r.presets.GetPreset(name).EffectChain)
+}
+
+func (r *spilloverRig) run(frames int, impulse bool) []float32 {
+	out := make([]float32, 0, frames)
+	for len(out) < frames {
+		r.buf.Resize(1, spilloverFrames)
+		r.buf.Clear()
+		if impulse && len(out) == 0 {
+			r.buf.Channel(0)[0] = 1
+		}
+		r.chain.Process(r.buf)
+		out = append(out, r.buf.Channel(0)...)
+	}
+	return out
+}
+
+func TestReverb(t *testing.T) {
+	t.Run("Process", func(t *testing.T) {
+		t.Run("should decay by about 60 dB over the decay time", func(t *testing.T) {
+			sut := newTestBuiltin(t, "reverb", map[string]float64{"decay": 1, "damping": 0, "pre-delay": 0, "mix": 100})
+			got := impulse(2 * testSampleRate)
+
+			sut.Process(&effects.ProcessContext{SampleRate: testSampleRate}, [][]float32{got})
+
+			early := rmsDb(got[testSampleRate/10 : testSampleRate/5])
+			late := rmsDb(got[testSampleRate+testSampleRate/10 : testSampleRate+testSampleRate/5])
+			if drop := early - late; drop < 50 || drop > 70 {
+				t.Errorf("got %.1f dB drop after decay time, want about 60", drop)
+			}
+		})
+	})
+
+	t.Run("Spillover", func(t *testing.T) {
+		const sampleRate = 8000
+
+		const tailMaxSeconds = 10
+
+		t.Run("should keep the reverb tail ringing after a preset switch", func(t *testing.T) {
+			sut := newSpilloverRig(t, sampleRate, true, 2)
+			sut.run(sampleRate/10, true)
+
+			sut.switchTo("clean")
+			got := sut.run(sampleRate, false)
+
+			if db := rmsDb(got[sampleRate/2 : sampleRate]); db < -70 {
+				t.Errorf("got %.1f dB half a second after the switch, want an audible tail", db)
+			}
+		})
+
+		t.Run("should cut the reverb tail on switch without spillover", func(t *testing.T) {
+			sut := newSpilloverRig(t, sampleRate, false, 2)
+			sut.run(sampleRate/10, true)
+
+			sut.switchTo("clean")
+			got := sut.run(sampleRate/2, false)
+
+			if db := rmsDb(got[sampleRate/10:]); !math.IsInf(db, -1) && db > -120 {
+				t.Errorf("got %.1f dB after the crossfade, want silence", db)
+			}
+		})
+
+		t.Run("should drop a long tail after the spillover age limit", func(t *testing.T) {
+			sut := newSpilloverRig(t, sampleRate, true, 10)
+			sut.run(sampleRate/10, true)
+
+			sut.switchTo("clean")
+			got := sut.run((tailMaxSeconds+1)*sampleRate, false)
+
+			if db := rmsDb(got[5*sampleRate : 6*sampleRate]); db < -100 {
+				t.Errorf("got %.1f dB five seconds after the switch, want the tail still ringing", db)
+			}
+			for i, s := range got[(tailMaxSeconds*sampleRate)+spilloverFrames:] {
+				if s != 0 {
+					t.Fatalf("got %v at %d past the age limit, want silence", s, i)
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,41 @@
+package builtin
+
+import "math"
+
+const defaultBPM = 120
+
+type noteValue struct {
+	label string
+	beats float64
+}
+
+var noteValues = []noteValue{
+	{"1/1", 4},
+	{"1/2.", 3},
+	{"1/2", 2},
+	{"1/4.", 1.5},
+	{"1/4", 1},
+	{"1/4T", 2.0 / 3},
+	{"1/8.", 0.75},
+	{"1/8", 0.5},
+	{"1/8T", 1.0 / 3},
+	{"1/16.", 0.375},
+	{"1/16", 0.25},
+	{"1/16T", 1.0 / 6},
+}
+
+func noteLabels() []string {
+	labels := make([]string, len(noteValues))
+	for i, note := range noteValues {
+		labels[i] = note.label
+	}
+	return labels
+}
+
+func noteSeconds(index, bpm float64) float64 {
+	if bpm <= 0 {
+		bpm = defaultBPM
+	}
+	i := max(0, min(int(math.Round(index)), len(noteValues)-1))
+	return noteValues[i].beats * 60 / bpm
+}
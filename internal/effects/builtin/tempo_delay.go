@@ -0,0 +1,129 @@
+package builtin
+
+import (
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const (
+	delayNote = iota
+	delayFeedback
+	delayLowCut
+	delayHighCut
+	delayPingPong
+	delayModRate
+	delayModDepth
+	delayMix
+)
+
+const (
+	delayMaxSeconds = 4.0
+
+	delayGlideMs = 300
+)
+
+var tempoDelayParams = []effects.Param{
+	{Name: "note", Min: 0, Max: float64(len(noteValues) - 1), Default: 7, Step: 1, Labels: noteLabels()},
+	{Name: "feedback", Min: 0, Max: 95, Default: 40, Step: 5, Unit: "%"},
+	{Name: "low cut", Min: 20, Max: 1000, Default: 100, Step: 20, Unit: "Hz"},
+	{Name: "high cut", Min: 1000, Max: 16000, Default: 5000, Step: 500, Unit: "Hz"},
+	{Name: "ping pong", Min: 0, Max: 1, Default: 0, Step: 1, Labels: []string{"off", "on"}},
+	{Name: "mod rate", Min: 0.1, Max: 5, Default: 0.6, Step: 0.1, Unit: "Hz"},
+	{Name: "mod depth", Min: 0, Max: 5, Default: 0.3, Step: 0.1, Unit: "ms"},
+	{Name: "mix", Min: 0, Max: 100, Default: 30, Step: 5, Unit: "%"},
+}
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:    "tempo delay",
+		Params:  tempoDelayParams,
+		Factory: newTempoDelay,
+	})
+}
+
+type tempoDelay struct {
+	sampleRate float64
+	params     *effects.ParamValues
+
+	lines   [dsp.MaxChannels]*dsp.DelayLine
+	lowCut  [dsp.MaxChannels]dsp.OnePole
+	highCut [dsp.MaxChannels]dsp.OnePole
+	lfo     dsp.LFO
+
+	delay     float64
+	glide     float64
+	lowCutHz  float64
+	highCutHz float64
+}
+
+func newTempoDelay(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	d := &tempoDelay{
+		sampleRate: float64(cfg.SampleRate),
+		params:     cfg.Params,
+	}
+	d.glide = float64(dsp.SmoothingCoeff(delayGlideMs, d.sampleRate))
+	for ch := range d.lines {
+		d.lines[ch] = dsp.NewDelayLine(int(delayMaxSeconds * d.sampleRate))
+	}
+	return d, nil
+}
+
+func (d *tempoDelay) updateFilters() {
+	low, high := d.params.Get(delayLowCut), d.params.Get(delayHighCut)
+	if low == d.lowCutHz && high == d.highCutHz {
+		return
+	}
+	d.lowCutHz, d.highCutHz = low, high
+	for ch := range d.lowCut {
+		d.lowCut[ch].SetCutoff(low, d.sampleRate)
+		d.highCut[ch].SetCutoff(high, d.sampleRate)
+	}
+}
+
+func (d *tempoDelay) feedback(ch int, x float32) float32 {
+	return d.highCut[ch].Lowpass(d.lowCut[ch].Highpass(x))
+}
+
+func (d *tempoDelay) Process(ctx *effects.ProcessContext, channels [][]float32) {
+	d.updateFilters()
+
+	depth := d.params.Get(delayModDepth) * d.sampleRate / 1000
+	target := noteSeconds(d.params.Get(delayNote), ctx.BPM) * d.sampleRate
+	target = min(target, d.lines[0].MaxDelay()-depth-1)
+	if d.delay == 0 {
+		d.delay = target
+	}
+
+	fb := float32(d.params.Get(delayFeedback) / 100)
+	rate := d.params.Get(delayModRate)
+	wet := float32(d.params.Get(delayMix) / 100)
+	pingPong := len(channels) > 1 && d.params.Get(delayPingPong) >= 0.5
+
+	for i := range channels[0] {
+		d.delay += (target - d.delay) * d.glide
+		d.lfo.Advance(rate, d.sampleRate)
+
+		if pingPong {
+			t := d.delay + float64(d.lfo.Sine(0))*depth
+			left, right := channels[0][i], channels[1][i]
+			yl, yr := d.lines[0].Read(t), d.lines[1].Read(t)
+
+			d.lines[0].Write((left+right)/2 + fb*d.feedback(0, yr))
+			d.lines[1].Write(fb * d.feedback(1, yl))
+
+			channels[0][i] = left*(1-wet) + yl*wet
+			channels[1][i] = right*(1-wet) + yr*wet
+			continue
+		}
+
+		for ch, samples := range channels {
+			t := d.delay + float64(d.lfo.Sine(0.25*float64(ch)))*depth
+			x := samples[i]
+			y := d.lines[ch].Read(t)
+
+			d.lines[ch].Write(x + fb*d.feedback(ch, y))
+
+			samples[i] = x*(1-wet) + y*wet
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package builtin
+
+import (
+	"testing"
+
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+func TestTempoDelay(t *testing.T) {
+	t.Run("Process", func(t *testing.T) {
+		t.Run("should echo after the note value at the current tempo", func(t *testing.T) {
+			sut := newTestBuiltin(t, "tempo delay", map[string]float64{"feedback": 0, "mod depth": 0, "mix": 100})
+			got := impulse(testSampleRate)
+
+			sut.Process(&effects.ProcessContext{SampleRate: testSampleRate, BPM: 120}, [][]float32{got})
+
+			if want := testSampleRate / 4; peakIndex(got) != want {
+				t.Errorf("got echo at %d, want %d", peakIndex(got), want)
+			}
+		})
+
+		t.Run("should bounce repeats between channels in ping pong mode", func(t *testing.T) {
+			sut := newTestBuiltin(t, "tempo delay", map[string]float64{"feedback": 80, "ping pong": 1, "mod depth": 0, "mix": 100})
+			left, right := impulse(testSampleRate), impulse(testSampleRate)
+
+			sut.Process(&effects.ProcessContext{SampleRate: testSampleRate, BPM: 120}, [][]float32{left, right})
+
+			if got, want := peakIndex(left), testSampleRate/4; got != want {
+				t.Errorf("got left echo at %d, want %d", got, want)
+			}
+			if got, want := peakIndex(right), testSampleRate/2; got != want {
+				t.Errorf("got right echo at %d, want %d", got, want)
+			}
+		})
+	})
+}
@@ -0,0 +1,87 @@
+package builtin
+
+import "math"
+
+const (
+	stackC1 = 250e-12
+	stackC2 = 20e-9
+	stackC3 = 20e-9
+	stackR1 = 250e3
+	stackR2 = 1e6
+	stackR3 = 25e3
+	stackR4 = 56e3
+)
+
+type toneStack struct {
+	b [4]float64
+	a [4]float64
+	x [3]float64
+	y [3]float64
+
+	bass, mid, treble float64
+}
+
+func (s *toneStack) set(bass, mid, treble, sampleRate float64) {
+	if bass == s.bass && mid == s.mid && treble == s.treble && s.a[0] != 0 {
+		return
+	}
+	s.bass, s.mid, s.treble = bass, mid, treble
+
+	const (
+		c1, c2, c3 = stackC1, stackC2, stackC3
+		r1, r2, r3 = stackR1, stackR2, stackR3
+		r4         = stackR4
+	)
+
+	l := math.Pow(bass, 3.5)
+	m := mid
+	t := treble
+
+	b1 := t*c1*r1 + m*c3*r3 + l*(c1*r2+c2*r2) + (c1*r3 + c2*r3)
+	b2 := t*(c1*c2*r1*r4+c1*c3*r1*r4) - m*m*(c1*c3*r3*r3+c2*c3*r3*r3) +
+		m*(c1*c3*r1*r3+c1*c3*r3*r3+c2*c3*r3*r3) + l*(c1*c2*r1*r2+c1*c2*r2*r4+c1*c3*r2*r4) +
+		l*m*(c1*c3*r2*r3+c2*c3*r2*r3) + (c1*c2*r1*r3 + c1*c2*r3*r4 + c1*c3*r3*r4)
+	b3 := l*m*(c1*c2*c3*r1*r2*r3+c1*c2*c3*r2*r3*r4) - m*m*(c1*c2*c3*r1*r3*r3+c1*c2*c3*r3*r3*r4) +
+		m*(c1*c2*c3*r1*r3*r3+c1*c2*c3*r3*r3*r4) + t*c1*c2*c3*r1*r3*r4 - t*m*c1*c2*c3*r1*r3*r4 +
+		t*l*c1*c2*c3*r1*r2*r4
+
+	a1 := (c1*r1 + c1*r3 + c2*r3 + c2*r4 + c3*r4) + m*c3*r3 + l*(c1*r2+c2*r2)
+	a2 := m*(c1*c3*r1*r3-c2*c3*r3*r4+c1*c3*r3*r3+c2*c3*r3*r3) + l*m*(c1*c3*r2*r3+c2*c3*r2*r3) -
+		m*m*(c1*c3*r3*r3+c2*c3*r3*r3) + l*(c1*c2*r2*r4+c1*c2*r1*r2+c1*c3*r2*r4+c2*c3*r2*r4) +
+		(c1*c2*r1*r4 + c1*c3*r1*r4 + c1*c2*r3*r4 + c1*c2*r1*r3 + c1*c3*r3*r4 + c2*c3*r3*r4)
+	a3 := l*m*(c1*c2*c3*r1*r2*r3+c1*c2*c3*r2*r3*r4) - m*m*(c1*c2*c3*r1*r3*r3+c1*c2*c3*r3*r3*r4) +
+		m*(c1*c2*c3*r3*r3*r4+c1*c2*c3*r1*r3*r3-c1*c2*c3*r1*r3*r4) + l*c1*c2*c3*r1*r2*r4 +
+		c1*c2*c3*r1*r3*r4
+
+	k := 2 * sampleRate
+	k2, k3 := k*k, k*k*k
+
+	s.b = [4]float64{
+		-b1*k - b2*k2 - b3*k3,
+		-b1*k + b2*k2 + 3*b3*k3,
+		b1*k + b2*k2 - 3*b3*k3,
+		b1*k - b2*k2 + b3*k3,
+	}
+	s.a = [4]float64{
+		-1 - a1*k - a2*k2 - a3*k3,
+		-3 - a1*k + a2*k2 + 3*a3*k3,
+		-3 + a1*k + a2*k2 - 3*a3*k3,
+		-1 + a1*k - a2*k2 + a3*k3,
+	}
+	for i := range s.b {
+		s.b[i] /= s.a[0]
+	}
+	for i := 3; i >= 0; i-- {
+		s.a[i] /= s.a[0]
+	}
+}
+
+func (s *toneStack) process(x float32) float32 {
+	in := float64(x)
+	out := s.b[0]*in + s.b[1]*s.x[0] + s.b[2]*s.x[1] + s.b[3]*s.x[2] -
+		s.a[1]*s.y[0] - s.a[2]*s.y[1] - s.a[3]*s.y[2]
+
+	s.x[2], s.x[1], s.x[0] = s.x[1], s.x[0], in
+	s.y[2], s.y[1], s.y[0] = s.y[1], s.y[0], out
+	return float32(out)
+}
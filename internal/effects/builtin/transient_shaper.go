@@ -0,0 +1,89 @@
+package builtin
+
+import (
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/effects"
+	"github.com/chloyka/gorig/internal/onset"
+)
+
+const (
+	shaperAttack = iota
+	shaperSustain
+	shaperOutput
+)
+
+const (
+	shaperFastReleaseMs = 40
+
+	shaperSlowAttackMs = 25
+
+	shaperMaxGainDb = 24
+)
+
+var transientShaperParams = []effects.Param{
+	{Name: "attack", Min: -100, Max: 100, Default: 0, Step: 5, Unit: "%"},
+	{Name: "sustain", Min: -100, Max: 100, Default: 0, Step: 5, Unit: "%"},
+	{Name: "output", Min: -24, Max: 12, Default: 0, Step: 0.5, Unit: "dB"},
+}
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:    "transient shaper",
+		Params:  transientShaperParams,
+		Factory: newTransientShaper,
+	})
+}
+
+type transientShaper struct {
+	params *effects.ParamValues
+
+	fast        dsp.Envelope
+	slowAttack  dsp.Envelope
+	slowRelease dsp.Envelope
+
+	reduction effects.Meter
+}
+
+func newTransientShaper(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	sampleRate := float64(cfg.SampleRate)
+	detector := onset.DefaultConfig(float32(sampleRate))
+
+	s := &transientShaper{params: cfg.Params}
+	s.fast.SetTimes(float64(detector.AttackMs), shaperFastReleaseMs, sampleRate)
+	s.slowAttack.SetTimes(shaperSlowAttackMs, shaperFastReleaseMs, sampleRate)
+	s.slowRelease.SetTimes(float64(detector.AttackMs), float64(detector.ReleaseMs), sampleRate)
+	return s, nil
+}
+
+func (s *transientShaper) Process(_ *effects.ProcessContext, channels [][]float32) {
+	attack := s.params.Get(shaperAttack) / 100
+	sustain := s.params.Get(shaperSustain) / 100
+	output := s.params.Get(shaperOutput)
+
+	peakReduction := 0.0
+	for i := range channels[0] {
+		var peak float32
+		for _, ch := range channels {
+			peak = max(peak, abs(ch[i]))
+		}
+
+		fast := dsp.LinearToDb(float64(s.fast.Process(peak)))
+		slowAttack := dsp.LinearToDb(float64(s.slowAttack.Process(peak)))
+		slowRelease := dsp.LinearToDb(float64(s.slowRelease.Process(peak)))
+
+		gainDb := attack*max(0, fast-slowAttack) + sustain*max(0, slowRelease-fast)
+		gainDb = max(-shaperMaxGainDb, min(gainDb, shaperMaxGainDb))
+		peakReduction = max(peakReduction, -gainDb)
+
+		gain := float32(dsp.DbToLinear(gainDb + output))
+		for _, ch := range channels {
+			ch[i] *= gain
+		}
+	}
+
+	s.reduction.Store(peakReduction)
+}
+
+func (s *transientShaper) GainReductionDb() float64 {
+	return s.reduction.Load()
+}
@@ -0,0 +1,50 @@
+package builtin
+
+import "github.com/chloyka/gorig/internal/effects"
+
+const (
+	tremoloDepth = lfoParamCount + iota
+	tremoloShape
+	tremoloStereo
+)
+
+var tremoloParams = lfoParams(4, 15,
+	effects.Param{Name: "depth", Min: 0, Max: 100, Default: 60, Step: 5, Unit: "%"},
+	effects.Param{Name: "shape", Min: 0, Max: float64(len(lfoShapeLabels) - 1), Default: lfoSine, Step: 1, Labels: lfoShapeLabels},
+	effects.Param{Name: "stereo", Min: 0, Max: 1, Default: 0, Step: 1, Labels: []string{"off", "on"}},
+)
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:    "tremolo",
+		Params:  tremoloParams,
+		Factory: newTremolo,
+	})
+}
+
+type tremolo struct {
+	modulator
+}
+
+func newTremolo(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	return &tremolo{modulator{sampleRate: float64(cfg.SampleRate), params: cfg.Params}}, nil
+}
+
+func (t *tremolo) Process(ctx *effects.ProcessContext, channels [][]float32) {
+	t.begin(ctx)
+
+	depth := float32(t.params.Get(tremoloDepth) / 100)
+	shape := int(t.params.Get(tremoloShape))
+	offset := 0.0
+	if t.params.Get(tremoloStereo) >= 0.5 {
+		offset = 0.5
+	}
+
+	for i := range channels[0] {
+		for ch, samples := range channels {
+			mod := 0.5 * (1 + t.shape(shape, offset*float64(ch)))
+			samples[i] *= 1 - depth*mod
+		}
+		t.advance()
+	}
+}
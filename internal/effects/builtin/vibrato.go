@@ -0,0 +1,24 @@
+package builtin
+
+import "github.com/chloyka/gorig/internal/effects"
+
+var vibratoParams = lfoParams(5, 12,
+	effects.Param{Name: "depth", Min: 0, Max: 3, Default: 0.7, Step: 0.1, Unit: "ms"},
+)
+
+func init() {
+	effects.RegisterBuiltin(effects.BuiltinSpec{
+		Name:    "vibrato",
+		Params:  vibratoParams,
+		Factory: newVibrato,
+	})
+}
+
+func newVibrato(cfg effects.BuiltinConfig) (effects.Processor, error) {
+	return newModDelay(cfg, modDelaySpec{
+		baseMs:     1,
+		maxDepthMs: 3,
+		feedback:   -1,
+		mix:        -1,
+	}), nil
+}
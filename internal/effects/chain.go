@@ -1,77 +1,102 @@
 package effects
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	configTypes "github.com/chloyka/gorig/internal/config/types"
+	"github.com/chloyka/gorig/internal/dsp"
 	"github.com/chloyka/gorig/internal/logger"
 	"github.com/chloyka/gorig/internal/logger/keys"
+	"github.com/chloyka/gorig/internal/rhythm"
 )
 
 type Chain struct {
-	mu            sync.RWMutex
+	mu            sync.Mutex
 	registry      *EffectRegistry
-	activeChain   []*InterpretedEffect
 	effectsDir    string
+	irDir         string
 	logger        *logger.Logger
-	enabled       bool
 	stateConfig   *configTypes.StateConfig
 	presetsConfig *configTypes.PresetsConfig
+
+	snapshot atomic.Pointer[chainSnapshot]
+	pending  atomic.Pointer[pendingChain]
+
+	current    *chainSnapshot
+	transition *transition
+	head       dsp.Buffer
+	tail       dsp.Buffer
+
+	ctx     ProcessContext
+	dry     *dsp.Buffer
+	dryHead dsp.Buffer
+	dryTail dsp.Buffer
 }
 
-func NewChain(log *logger.Logger, effectsDir string, stateConfig *configTypes.StateConfig, presetsConfig *configTypes.PresetsConfig) *Chain {
+type pendingChain struct {
+	snapshot *chainSnapshot
+	boundary rhythm.Boundary
+}
+
+func NewChain(
+	log *logger.Logger,
+	effectsDir string,
+	irDir string,
+	stateConfig *configTypes.StateConfig,
+	presetsConfig *configTypes.PresetsConfig,
+	transitionCfg TransitionConfig,
+) *Chain {
 	c := &Chain{
 		effectsDir:    effectsDir,
+		irDir:         irDir,
 		logger:        log,
-		enabled:       stateConfig.EffectsEnabled,
 		stateConfig:   stateConfig,
 		presetsConfig: presetsConfig,
+		transition:    newTransition(transitionCfg),
+		ctx:           ProcessContext{SampleRate: transitionCfg.SampleRate},
+		dry:           dsp.NewBuffer(dsp.MaxChannels, transitionCfg.MaxFrames),
 	}
 
-	if err := c.reload(); err != nil {
+	registry, err := c.loadRegistry()
+	if err != nil {
 		log.Error("failed to load effects", keys.Error(err))
+		registry = NewEffectRegistry()
 	}
 
-	c.applyActivePreset()
+	c.registry = registry
+	c.current = c.buildActivePresetChain(registry)
+	c.current.enabled = stateConfig.EffectsEnabled
+	c.snapshot.Store(c.current)
 
 	return c
 }
 
-func (c *Chain) reload() error {
+func (c *Chain) loadRegistry() (*EffectRegistry, error) {
 	registry, err := loadEffectsFromDirRecursive(c.effectsDir)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	c.registry = registry
-
 	names := registry.GetAvailableEffectNames()
 	c.logger.Info("effects loaded",
 		keys.EffectList(names),
 	)
 
-	return nil
+	return registry, nil
 }
 
-func (c *Chain) applyActivePreset() {
+func (c *Chain) buildActivePresetChain(registry *EffectRegistry) *chainSnapshot {
 	preset := c.presetsConfig.GetActivePresetConfig()
 	if preset == nil {
-		c.activeChain = nil
 		c.logger.Debug("no active preset, chain empty")
-		return
+		return &chainSnapshot{split: -1}
 	}
 
-	c.activeChain = make([]*InterpretedEffect, 0)
-	var missingEffects []string
-
-	for _, effectName := range preset.EffectChain {
-		effect := c.registry.GetEffect(effectName)
-		if effect != nil {
-			c.activeChain = append(c.activeChain, effect)
-		} else {
-			missingEffects = append(missingEffects, effectName)
-		}
-	}
+	chain, missingEffects := c.buildChain(registry, preset.EffectChain, preset.StereoSplit, preset.Slots)
 
 	if len(missingEffects) > 0 {
 		c.logger.Warn("preset has missing effects",
@@ -83,126 +108,445 @@ func (c *Chain) applyActivePreset() {
 	c.logger.Info("preset chain applied",
 		keys.EffectName(preset.Name),
 	)
+
+	return chain
 }
 
-func (c *Chain) SetPresetChain(effectNames []string) {
+func (c *Chain) buildChain(registry *EffectRegistry, effectNames []string, stereoSplit *int, slots []configTypes.SlotSettings) (*chainSnapshot, []string) {
+	chain := &chainSnapshot{
+		stages: make([]chainStage, 0, len(effectNames)),
+		split:  -1,
+	}
+	var missing []string
+
+	for i, name := range effectNames {
+		if stereoSplit != nil && *stereoSplit == i {
+			chain.split = len(chain.stages)
+		}
+
+		var slot configTypes.SlotSettings
+		if i < len(slots) {
+			slot = slots[i]
+		}
+
+		stage, err := c.newChainStage(registry, name, slot)
+		if err != nil {
+			c.logger.Warn("failed to create effect", keys.EffectName(name), keys.Error(err))
+			missing = append(missing, name)
+			chain.failures = append(chain.failures, stageFailure{
+				slot:   i,
+				name:   name,
+				stereo: stereoSplit != nil && i >= *stereoSplit,
+				err:    err,
+			})
+			continue
+		}
+		stage.slot = i
+		chain.stages = append(chain.stages, stage)
+	}
+
+	return chain, missing
+}
+
+func (c *Chain) newChainStage(registry *EffectRegistry, name string, slot configTypes.SlotSettings) (chainStage, error) {
+	ctx := &c.ctx
+	maxFrames := c.transition.cfg.MaxFrames
+	var over *oversampling
+	if ValidOversample(slot.Oversample) {
+		over = newOversampling(slot.Oversample, &c.ctx, maxFrames)
+		ctx = &over.ctx
+		maxFrames *= slot.Oversample
+	}
+
+	if spec, ok := builtins[name]; ok && !registry.HasScript(name) {
+		effect, err := newBuiltinEffect(spec, ctx, maxFrames, slot, c.irDir)
+		if err != nil {
+			return chainStage{}, err
+		}
+		return chainStage{instances: []stageEffect{effect}, oversampling: over}, nil
+	}
+
+	first, err := registry.NewEffect(name)
+	if err != nil {
+		return chainStage{}, err
+	}
+	first.SetSampleRate(ctx.SampleRate)
+
+	stage := chainStage{instances: []stageEffect{first}, oversampling: over}
+	if first.ChannelAware() {
+		return stage, nil
+	}
+
+	for len(stage.instances) < dsp.MaxChannels {
+		effect, err := first.NewInstance()
+		if err != nil {
+			return chainStage{}, err
+		}
+		effect.SetSampleRate(ctx.SampleRate)
+		stage.instances = append(stage.instances, effect)
+	}
+
+	return stage, nil
+}
+
+func (c *Chain) activeStereoSplit() *int {
+	preset := c.presetsConfig.GetActivePresetConfig()
+	if preset == nil {
+		return nil
+	}
+	return preset.StereoSplit
+}
+
+func (c *Chain) activeSlots() []configTypes.SlotSettings {
+	preset := c.presetsConfig.GetActivePresetConfig()
+	if preset == nil {
+		return nil
+	}
+	return preset.Slots
+}
+
+func (c *Chain) currentRegistry() *EffectRegistry {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	return c.registry
+}
 
-	c.activeChain = make([]*InterpretedEffect, 0)
-
-	for _, name := range effectNames {
-		effect := c.registry.GetEffect(name)
-		if effect != nil {
-			c.activeChain = append(c.activeChain, effect)
+func (c *Chain) publish(chain *chainSnapshot) {
+	for {
+		old := c.snapshot.Load()
+		chain.enabled = old.enabled
+		if c.snapshot.CompareAndSwap(old, chain) {
+			return
 		}
 	}
+}
+
+func (c *Chain) SetPresetChain(effectNames []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	chain, _ := c.buildChain(c.registry, effectNames, c.activeStereoSplit(), c.activeSlots())
+
+	c.pending.Store(nil)
+	c.publish(chain)
 
 	c.logger.Debug("chain updated from preset")
 }
 
-func (c *Chain) Reload() error {
+func (c *Chain) QueuePresetChain(effectNames []string, boundary rhythm.Boundary) {
+	if boundary == rhythm.BoundaryNone {
+		c.SetPresetChain(effectNames)
+		return
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	chain, _ := c.buildChain(c.registry, effectNames, c.activeStereoSplit(), c.activeSlots())
+	chain.enabled = c.IsChainEnabled()
+
+	c.pending.Store(&pendingChain{
+		snapshot: chain,
+		boundary: boundary,
+	})
+
+	c.logger.Debug("chain switch queued", keys.RhythmBoundary(boundary.String()))
+}
+
+func (c *Chain) PendingBoundary() rhythm.Boundary {
+	pending := c.pending.Load()
+	if pending == nil {
+		return rhythm.BoundaryNone
+	}
+	return pending.boundary
+}
+
+func (c *Chain) Reload() error {
 	c.logger.Debug("reloading effects from disk")
-	if err := c.reload(); err != nil {
+
+	registry, err := c.loadRegistry()
+	if err != nil {
 		return err
 	}
 
-	c.applyActivePreset()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.registry = registry
+	chain := c.buildActivePresetChain(registry)
+
+	c.pending.Store(nil)
+	c.publish(chain)
+
 	return nil
 }
 
-func (c *Chain) Process(samples []float32) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+func (c *Chain) SetTransport(bpm float64, onset bool) {
+	c.ctx.BPM = bpm
+	c.ctx.Onset = onset
+}
+
+func (c *Chain) Process(buf *dsp.Buffer) {
+	c.dry.CopyFrom(buf)
+	c.process(buf, c.dry)
+}
+
+func (c *Chain) process(buf, dry *dsp.Buffer) {
+	c.syncSnapshot()
+	c.ctx.Dry = dry.Views()
+	c.transition.process(c.current.effective(), buf)
+}
 
-	if !c.enabled {
+func (c *Chain) ProcessSwitching(buf *dsp.Buffer, switchAt int) {
+	c.dry.CopyFrom(buf)
+
+	pending := c.pending.Load()
+	if pending == nil || switchAt < 0 || switchAt > buf.Frames() {
+		c.process(buf, c.dry)
 		return
 	}
 
-	for _, effect := range c.activeChain {
-		effect.Process(samples)
+	buf.Slice(&c.head, 0, switchAt)
+	c.dry.Slice(&c.dryHead, 0, switchAt)
+	c.process(&c.head, &c.dryHead)
+
+	if c.pending.CompareAndSwap(pending, nil) {
+		c.snapshot.Store(pending.snapshot)
+	}
+
+	buf.Slice(&c.tail, switchAt, buf.Frames())
+	c.dry.Slice(&c.dryTail, switchAt, c.dry.Frames())
+	c.process(&c.tail, &c.dryTail)
+
+	if c.head.Channels() < c.tail.Channels() {
+		c.head.SplitToStereo()
+	} else if c.tail.Channels() < c.head.Channels() {
+		c.tail.SplitToStereo()
 	}
+	buf.Resize(max(c.head.Channels(), c.tail.Channels()), buf.Frames())
 }
 
-func (c *Chain) GetAvailableEffectNames() []string {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+func (c *Chain) SetStreamFormat(sampleRate, maxFrames int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	if c.registry == nil {
+	cfg := c.transition.cfg
+	if cfg.SampleRate == sampleRate && cfg.MaxFrames >= maxFrames {
+		return
+	}
+
+	c.ctx.SampleRate = sampleRate
+	c.dry.Resize(dsp.MaxChannels, max(cfg.MaxFrames, maxFrames))
+
+	if cfg.SampleRate > 0 {
+		cfg.FadeFrames = cfg.FadeFrames * sampleRate / cfg.SampleRate
+	}
+	cfg.SampleRate = sampleRate
+	cfg.MaxFrames = max(cfg.MaxFrames, maxFrames)
+
+	c.transition = newTransition(cfg)
+
+	c.pending.Store(nil)
+
+	snap := c.buildActivePresetChain(c.registry)
+	snap.enabled = c.snapshot.Load().enabled
+	c.current = snap
+	c.snapshot.Store(snap)
+}
+
+func (c *Chain) syncSnapshot() {
+	snap := c.snapshot.Load()
+	if snap == c.current {
+		return
+	}
+
+	c.transition.start(c.current.effective(), snap.effective())
+	c.current = snap
+}
+
+func (c *Chain) GetAvailableEffectNames() []string {
+	registry := c.currentRegistry()
+	if registry == nil {
 		return nil
 	}
-	return c.registry.GetAvailableEffectNames()
+	return registry.GetAvailableEffectNames()
 }
 
 func (c *Chain) HasActiveEffects() bool {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return len(c.activeChain) > 0
+	return len(c.snapshot.Load().stages) > 0
 }
 
 type EffectInfo struct {
-	Name      string
-	Available bool
+	Name          string
+	Available     bool
+	Stereo        bool
+	Oversample    int
+	LatencyFrames int
+	Err           error
 }
 
 func (c *Chain) GetActiveChainInfo() []EffectInfo {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	snap := c.snapshot.Load()
 
 	var infos []EffectInfo
-	for _, effect := range c.activeChain {
+	failures := snap.failures
+	for i, stage := range snap.stages {
+		for len(failures) > 0 && failures[0].slot < stage.slot {
+			infos = append(infos, failures[0].info())
+			failures = failures[1:]
+		}
 		infos = append(infos, EffectInfo{
-			Name:      effect.Name(),
-			Available: true,
+			Name:          stage.name(),
+			Available:     true,
+			Stereo:        snap.split >= 0 && i >= snap.split,
+			Oversample:    stage.oversample(),
+			LatencyFrames: stage.latencyFrames(),
 		})
 	}
+	for _, failure := range failures {
+		infos = append(infos, failure.info())
+	}
 	return infos
 }
 
+func (f stageFailure) info() EffectInfo {
+	return EffectInfo{Name: f.name, Stereo: f.stereo, Err: f.err}
+}
+
+func (c *Chain) EffectTimings() []EffectTiming {
+	stages := c.snapshot.Load().stages
+
+	timings := make([]EffectTiming, 0, len(stages))
+	for _, stage := range stages {
+		timings = append(timings, stage.timing())
+	}
+	return timings
+}
+
+func (c *Chain) LatencyFrames() int {
+	return c.snapshot.Load().effective().latencyFrames()
+}
+
+type EffectMeter struct {
+	Name            string
+	GainReductionDb float64
+}
+
+func (c *Chain) EffectMeters() []EffectMeter {
+	var meters []EffectMeter
+	for _, stage := range c.snapshot.Load().stages {
+		effect, ok := stage.builtin()
+		if !ok {
+			continue
+		}
+		if gr, ok := effect.GainReductionDb(); ok {
+			meters = append(meters, EffectMeter{Name: stage.name(), GainReductionDb: gr})
+		}
+	}
+	return meters
+}
+
+func (c *Chain) SetSlotParam(slot int, name string, value float64) bool {
+	applied := false
+	apply := func(snap *chainSnapshot) {
+		for _, stage := range snap.stages {
+			if stage.slot != slot {
+				continue
+			}
+			if effect, ok := stage.builtin(); ok && effect.Params().Set(name, value) {
+				applied = true
+			}
+		}
+	}
+
+	apply(c.snapshot.Load())
+	if pending := c.pending.Load(); pending != nil {
+		apply(pending.snapshot)
+	}
+	return applied
+}
+
+func (c *Chain) AvailableIRs() []string {
+	entries, err := os.ReadDir(c.irDir)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".wav") {
+			names = append(names, entry.Name())
+		}
+	}
+	return names
+}
+
 func (c *Chain) GetEffects() []EffectInfo {
 	return c.GetActiveChainInfo()
 }
 
 func (c *Chain) ToggleChain() bool {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	enabled := !c.IsChainEnabled()
+
+	for {
+		old := c.pending.Load()
+		if old == nil || old.snapshot.enabled == enabled {
+			break
+		}
+		next := &pendingChain{
+			snapshot: old.snapshot.withEnabled(enabled),
+			boundary: old.boundary,
+		}
+		if c.pending.CompareAndSwap(old, next) {
+			break
+		}
+	}
+
+	for {
+		old := c.snapshot.Load()
+		if old.enabled == enabled {
+			break
+		}
+		if c.snapshot.CompareAndSwap(old, old.withEnabled(enabled)) {
+			break
+		}
+	}
 
-	c.enabled = !c.enabled
-	c.logger.Debug("chain toggled", keys.EffectEnabled(c.enabled))
+	c.logger.Debug("chain toggled", keys.EffectEnabled(enabled))
 
-	c.stateConfig.SetEffectsEnabled(c.enabled)
+	c.stateConfig.SetEffectsEnabled(enabled)
 
-	return c.enabled
+	return enabled
 }
 
 func (c *Chain) IsChainEnabled() bool {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return c.enabled
+	return c.snapshot.Load().enabled
 }
 
 func (c *Chain) GetRegistry() *EffectRegistry {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-	return c.registry
+	return c.currentRegistry()
 }
 
 func (c *Chain) GetMissingEffectsForPreset(preset *configTypes.Preset) []string {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	registry := c.currentRegistry()
 
-	if preset == nil || c.registry == nil {
+	if preset == nil || registry == nil {
 		return nil
 	}
 
 	var missing []string
 	for _, name := range preset.EffectChain {
-		if c.registry.GetEffect(name) == nil {
+		if !registry.Has(name) {
 			missing = append(missing, name)
 		}
 	}
+
+	if preset.Name == c.presetsConfig.ActivePreset {
+		for _, failure := range c.snapshot.Load().failures {
+			if registry.Has(failure.name) {
+				missing = append(missing, failure.name)
+			}
+		}
+	}
 	return missing
 }
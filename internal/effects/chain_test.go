@@ -0,0 +1,552 @@
+package effects
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	configTypes "github.com/chloyka/gorig/internal/config/types"
+	"github.com/chloyka/gorig/internal/dsp"
+	"github.com/chloyka/gorig/internal/logger"
+	"github.com/chloyka/gorig/internal/rhythm"
+	errs "github.com/chloyka/gorig/utils/errors"
+	"github.com/traefik/yaegi/interp"
+	"github.com/traefik/yaegi/stdlib"
+	"go.uber.org/zap"
+)
+
+const testFrames = 256
+
+func newTestChain(t *testing.T, cfg TransitionConfig) *Chain {
+	t.Helper()
+
+	log := &logger.Logger{Logger: zap.NewNop()}
+	state := &configTypes.StateConfig{EffectsEnabled: true}
+	presets := &configTypes.PresetsConfig{}
+
+	return NewChain(log, t.TempDir(), t.TempDir(), state, presets, cfg)
+}
+
+const sampleRateScript = `package effects
+
+var Name = "rate probe"
+
+var rate = 0
+
+func SetSampleRate(sampleRate int) { rate = sampleRate }
+
+func Process(samples []float32) {
+	for i := range samples {
+		samples[i] = float32(rate)
+	}
+}
+`
+
+const halfGainScript = `package effects
+
+var Name = "half"
+
+func Process(samples []float32) {
+	for i := range samples {
+		samples[i] *= 0.5
+	}
+}
+`
+
+const stereoHalfScript = `package effects
+
+var Name = "stereo half"
+
+func ProcessChannels(channels [][]float32) {
+	for _, samples := range channels {
+		for i := range samples {
+			samples[i] *= 0.5
+		}
+	}
+}
+`
+
+func newRawScriptCall(t *testing.T, source string) func() {
+	t.Helper()
+
+	i := interp.New(interp.Options{})
+	if err := i.Use(stdlib.Symbols); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := i.Eval(source); err != nil {
+		t.Fatal(err)
+	}
+
+	samples := make([]float32, testFrames)
+	channels := [][]float32{samples}
+	if v, err := i.Eval("effects.Process"); err == nil {
+		fn := v.Interface().(func([]float32))
+		return func() { fn(samples) }
+	}
+	v, err := i.Eval("effects.ProcessChannels")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn := v.Interface().(func([][]float32))
+	return func() { fn(channels) }
+}
+
+type rateProbe struct {
+	rate int
+}
+
+func (p *rateProbe) Process(*ProcessContext, [][]float32) {}
+
+func newGainEffect(name string, gain float32) *InterpretedEffect {
+	return &InterpretedEffect{
+		name:    name,
+		enabled: true,
+		processFn: func(samples []float32) {
+			for i := range samples {
+				samples[i] *= gain
+			}
+		},
+	}
+}
+
+func newPanEffect(name string, left, right float32) *InterpretedEffect {
+	e := &InterpretedEffect{name: name, enabled: true}
+	e.channelsFn = func(channels [][]float32) {
+		for i := range channels[0] {
+			channels[0][i] *= left
+		}
+		if len(channels) > 1 {
+			for i := range channels[1] {
+				channels[1][i] *= right
+			}
+		}
+	}
+	e.processFn = func(samples []float32) {
+		e.channelsFn([][]float32{samples})
+	}
+	return e
+}
+
+func newTestSnapshot(split int, stages ...[]*InterpretedEffect) *chainSnapshot {
+	snap := &chainSnapshot{split: split}
+	for i, instances := range stages {
+		stage := chainStage{slot: i}
+		for _, effect := range instances {
+			stage.instances = append(stage.instances, effect)
+		}
+		snap.stages = append(snap.stages, stage)
+	}
+	return snap
+}
+
+func filledBuffer(value float32) *dsp.Buffer {
+	buf := dsp.NewBuffer(1, testFrames)
+	for i := range buf.Channel(0) {
+		buf.Channel(0)[i] = value
+	}
+	return buf
+}
+
+func TestChain(t *testing.T) {
+	t.Run("Process", func(t *testing.T) {
+		t.Run("should apply published chain on next buffer", func(t *testing.T) {
+			sut := newTestChain(t, TransitionConfig{SampleRate: 48000, MaxFrames: testFrames})
+			sut.publish(newTestSnapshot(-1, []*InterpretedEffect{newGainEffect("double", 2)}))
+			buf := filledBuffer(1)
+
+			sut.Process(buf)
+
+			if got := buf.Channel(0)[testFrames-1]; got != 2 {
+				t.Errorf("got %v, want 2", got)
+			}
+		})
+
+		t.Run("should split to stereo at configured position", func(t *testing.T) {
+			sut := newTestChain(t, TransitionConfig{SampleRate: 48000, MaxFrames: testFrames})
+			sut.publish(newTestSnapshot(1,
+				[]*InterpretedEffect{newGainEffect("double", 2)},
+				[]*InterpretedEffect{newPanEffect("pan", 1, 0.5)},
+				[]*InterpretedEffect{newGainEffect("left", 3), newGainEffect("right", 3)},
+			))
+			buf := filledBuffer(1)
+
+			sut.Process(buf)
+
+			if got := buf.Channels(); got != 2 {
+				t.Fatalf("got %d channels, want 2", got)
+			}
+			if got := buf.Channel(0)[0]; got != 6 {
+				t.Errorf("got left %v, want 6", got)
+			}
+			if got := buf.Channel(1)[0]; got != 3 {
+				t.Errorf("got right %v, want 3", got)
+			}
+		})
+
+		t.Run("should not allocate", func(t *testing.T) {
+			sut := newTestChain(t, TransitionConfig{SampleRate: 48000, MaxFrames: testFrames})
+			sut.publish(newTestSnapshot(-1, []*InterpretedEffect{newGainEffect("half", 0.5)}))
+			buf := filledBuffer(1)
+
+			got := testing.AllocsPerRun(100, func() {
+				sut.Process(buf)
+			})
+
+			if got != 0 {
+				t.Errorf("got %v allocs per run, want 0", got)
+			}
+		})
+
+		scripts := []struct {
+			name   string
+			source string
+		}{
+			{name: "should add no allocations around a mono script", source: halfGainScript},
+			{name: "should add no allocations around a channel-aware script", source: stereoHalfScript},
+		}
+
+		for _, tt := range scripts {
+			t.Run(tt.name, func(t *testing.T) {
+				script, err := evalEffect(tt.source)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sut := newTestChain(t, TransitionConfig{SampleRate: 48000, MaxFrames: testFrames})
+				sut.publish(newTestSnapshot(-1, []*InterpretedEffect{script}))
+				buf := filledBuffer(1)
+				sut.Process(buf)
+
+				want := testing.AllocsPerRun(100, newRawScriptCall(t, tt.source))
+				got := testing.AllocsPerRun(100, func() {
+					sut.Process(buf)
+				})
+
+				if got > want {
+					t.Errorf("got %v allocs per run, want at most the interpreter's own %v", got, want)
+				}
+			})
+		}
+
+		t.Run("should not allocate while crossfading with spillover", func(t *testing.T) {
+			sut := newTestChain(t, TransitionConfig{
+				SampleRate: 48000,
+				MaxFrames:  testFrames,
+				FadeFrames: testFrames * 1000,
+				Spillover:  true,
+			})
+			sut.publish(newTestSnapshot(-1, []*InterpretedEffect{newGainEffect("a", 0.5)}))
+			buf := filledBuffer(1)
+			sut.Process(buf)
+			sut.publish(newTestSnapshot(-1, []*InterpretedEffect{newGainEffect("b", 0.25)}))
+
+			got := testing.AllocsPerRun(100, func() {
+				sut.Process(buf)
+			})
+
+			if got != 0 {
+				t.Errorf("got %v allocs per run, want 0", got)
+			}
+		})
+
+		t.Run("should not block while writer holds the lock", func(t *testing.T) {
+			sut := newTestChain(t, TransitionConfig{SampleRate: 48000, MaxFrames: testFrames})
+			sut.publish(newTestSnapshot(-1, []*InterpretedEffect{newGainEffect("double", 2)}))
+			buf := filledBuffer(1)
+			done := make(chan struct{})
+
+			sut.mu.Lock()
+			defer sut.mu.Unlock()
+
+			go func() {
+				sut.Process(buf)
+				sut.ProcessSwitching(buf, 10)
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("Process blocked on chain lock")
+			}
+		})
+	})
+
+	t.Run("ProcessSwitching", func(t *testing.T) {
+		t.Run("should swap pending chain at switch point", func(t *testing.T) {
+			sut := newTestChain(t, TransitionConfig{SampleRate: 48000, MaxFrames: testFrames})
+			next := newTestSnapshot(-1, []*InterpretedEffect{newGainEffect("double", 2)})
+			next.enabled = true
+			sut.pending.Store(&pendingChain{snapshot: next, boundary: rhythm.BoundaryBeat})
+			buf := filledBuffer(1)
+
+			sut.ProcessSwitching(buf, 100)
+
+			if got := buf.Channel(0)[99]; got != 1 {
+				t.Errorf("before switch got %v, want 1", got)
+			}
+			if got := buf.Channel(0)[100]; got != 2 {
+				t.Errorf("after switch got %v, want 2", got)
+			}
+			if got := sut.PendingBoundary(); got != rhythm.BoundaryNone {
+				t.Errorf("got pending %v, want none", got)
+			}
+		})
+	})
+
+	t.Run("Oversampling", func(t *testing.T) {
+		newOversampledChain := func(t *testing.T) *Chain {
+			sut := newTestChain(t, TransitionConfig{SampleRate: 48000, MaxFrames: testFrames})
+			snap := newTestSnapshot(-1, []*InterpretedEffect{newGainEffect("double", 2)})
+			snap.stages[0].oversampling = newOversampling(4, &sut.ctx, testFrames)
+			sut.publish(snap)
+			return sut
+		}
+
+		t.Run("should process stage at the oversampled rate", func(t *testing.T) {
+			sut := newOversampledChain(t)
+			var buf *dsp.Buffer
+
+			for range 4 {
+				buf = filledBuffer(1)
+				sut.Process(buf)
+			}
+
+			if got := buf.Channel(0)[testFrames-1]; got < 1.99 || got > 2.01 {
+				t.Errorf("got %v, want 2", got)
+			}
+		})
+
+		t.Run("should oversample buffers larger than the oversampler in chunks", func(t *testing.T) {
+			sut := newTestChain(t, TransitionConfig{SampleRate: 48000, MaxFrames: testFrames})
+			var chunks []int
+			probe := &InterpretedEffect{name: "probe", enabled: true}
+			probe.processFn = func(samples []float32) {
+				chunks = append(chunks, len(samples))
+			}
+			snap := newTestSnapshot(-1, []*InterpretedEffect{probe})
+			snap.stages[0].oversampling = newOversampling(4, &sut.ctx, testFrames/4)
+			sut.publish(snap)
+
+			sut.Process(filledBuffer(1))
+
+			if want := []int{testFrames, testFrames, testFrames, testFrames}; !slices.Equal(chunks, want) {
+				t.Errorf("got chunks %v, want %v", chunks, want)
+			}
+		})
+
+		t.Run("should report filter latency", func(t *testing.T) {
+			sut := newOversampledChain(t)
+
+			got := sut.LatencyFrames()
+
+			if got != 16 {
+				t.Errorf("got %d latency frames, want 16", got)
+			}
+		})
+
+		t.Run("should not allocate", func(t *testing.T) {
+			sut := newOversampledChain(t)
+			buf := filledBuffer(1)
+
+			got := testing.AllocsPerRun(100, func() {
+				sut.Process(buf)
+			})
+
+			if got != 0 {
+				t.Errorf("got %v allocs per run, want 0", got)
+			}
+		})
+	})
+
+	t.Run("SetStreamFormat", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			oversample int
+			want       float32
+		}{
+			{name: "should pass stream sample rate to scripts", oversample: 0, want: 44100},
+			{name: "should pass oversampled rate to oversampled scripts", oversample: 2, want: 88200},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := newTestChain(t, TransitionConfig{SampleRate: 48000, MaxFrames: testFrames})
+				proto, err := evalEffect(sampleRateScript)
+				if err != nil {
+					t.Fatalf("eval script: %v", err)
+				}
+				registry := NewEffectRegistry()
+				registry.effects[proto.Name()] = proto
+
+				sut.SetStreamFormat(44100, testFrames)
+				stage, err := sut.newChainStage(registry, proto.Name(), configTypes.SlotSettings{Oversample: tt.oversample})
+				if err != nil {
+					t.Fatalf("new stage: %v", err)
+				}
+				got := make([]float32, 1)
+				for _, instance := range stage.instances {
+					instance.Process(got)
+
+					if got[0] != tt.want {
+						t.Errorf("got rate %v, want %v", got[0], tt.want)
+					}
+				}
+			})
+		}
+
+		t.Run("should not race with a concurrent preset switch", func(t *testing.T) {
+			RegisterBuiltin(BuiltinSpec{
+				Name: "rate probe",
+				Factory: func(cfg BuiltinConfig) (Processor, error) {
+					return &rateProbe{rate: cfg.SampleRate}, nil
+				},
+			})
+			chain := []string{"rate probe", "rate probe"}
+			log := &logger.Logger{Logger: zap.NewNop()}
+			presets := &configTypes.PresetsConfig{
+				Presets: []configTypes.Preset{{
+					Name:        "probe",
+					EffectChain: chain,
+					Slots:       []configTypes.SlotSettings{{}, {Oversample: 2}},
+				}},
+				ActivePreset: "probe",
+			}
+			state := &configTypes.StateConfig{EffectsEnabled: true}
+			sut := NewChain(log, t.TempDir(), t.TempDir(), state, presets, TransitionConfig{SampleRate: 48000, MaxFrames: testFrames})
+			done := make(chan struct{})
+
+			go func() {
+				defer close(done)
+				for range 500 {
+					sut.SetPresetChain(chain)
+				}
+			}()
+			rates := []int{44100, 48000}
+			for i := range 500 {
+				sut.SetStreamFormat(rates[i%2], testFrames)
+			}
+			<-done
+
+			want := rates[499%2]
+			for i, stage := range sut.snapshot.Load().stages {
+				effect, _ := stage.builtin()
+				if got := effect.proc.(*rateProbe).rate; got != want*max(1, i*2) {
+					t.Errorf("got stage %d built at %d Hz, want %d", i, got, want*max(1, i*2))
+				}
+			}
+		})
+	})
+
+	t.Run("GetActiveChainInfo", func(t *testing.T) {
+		t.Run("should report effects that failed to build", func(t *testing.T) {
+			RegisterBuiltin(BuiltinSpec{
+				Name: "broken ir",
+				Factory: func(BuiltinConfig) (Processor, error) {
+					return nil, errs.ErrWAVRead
+				},
+			})
+			sut := newTestChain(t, TransitionConfig{SampleRate: 48000, MaxFrames: testFrames})
+			sut.presetsConfig.Presets = []configTypes.Preset{{Name: "cab", EffectChain: []string{"broken ir"}}}
+			sut.presetsConfig.ActivePreset = "cab"
+
+			sut.SetPresetChain([]string{"broken ir"})
+			got := sut.GetActiveChainInfo()
+
+			if len(got) != 1 || got[0].Available || !errs.Is(got[0].Err, errs.ErrWAVRead) {
+				t.Errorf("got %+v, want unavailable broken ir with its error", got)
+			}
+			if missing := sut.GetMissingEffectsForPreset(sut.presetsConfig.GetActivePresetConfig()); !slices.Equal(missing, []string{"broken ir"}) {
+				t.Errorf("got missing %v, want [broken ir]", missing)
+			}
+		})
+	})
+
+	t.Run("newBuiltinEffect", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			ir      string
+			wantErr bool
+		}{
+			{name: "should accept an IR inside the IR directory", ir: "cab.wav"},
+			{name: "should accept an IR in a subdirectory", ir: filepath.Join("v30", "cab.wav")},
+			{name: "should reject an IR outside the IR directory", ir: filepath.Join("..", "cab.wav"), wantErr: true},
+			{name: "should reject an absolute IR path", ir: filepath.Join(string(filepath.Separator), "tmp", "cab.wav"), wantErr: true},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var gotPath string
+				spec := BuiltinSpec{
+					Name: "ir probe",
+					Factory: func(cfg BuiltinConfig) (Processor, error) {
+						gotPath = cfg.IRPath
+						return nil, nil
+					},
+				}
+				dir := t.TempDir()
+
+				_, err := newBuiltinEffect(spec, &ProcessContext{SampleRate: 48000}, testFrames, configTypes.SlotSettings{IR: tt.ir}, dir)
+
+				if tt.wantErr {
+					if !errs.Is(err, errs.ErrEffectsIRPath) {
+						t.Errorf("got %v, want %v", err, errs.ErrEffectsIRPath)
+					}
+					return
+				}
+				if err != nil || gotPath != filepath.Join(dir, tt.ir) {
+					t.Errorf("got path %q err %v, want %q", gotPath, err, filepath.Join(dir, tt.ir))
+				}
+			})
+		}
+	})
+
+	t.Run("ToggleChain", func(t *testing.T) {
+		t.Run("should bypass effects when disabled", func(t *testing.T) {
+			sut := newTestChain(t, TransitionConfig{SampleRate: 48000, MaxFrames: testFrames})
+			sut.publish(newTestSnapshot(-1, []*InterpretedEffect{newGainEffect("double", 2)}))
+			buf := filledBuffer(1)
+
+			got := sut.ToggleChain()
+			sut.Process(buf)
+
+			if got {
+				t.Error("got enabled, want disabled")
+			}
+			if buf.Channel(0)[0] != 1 {
+				t.Errorf("got %v, want dry 1", buf.Channel(0)[0])
+			}
+		})
+
+		tests := []struct {
+			name         string
+			blocksBypass int
+		}{
+			{name: "should not run a spillover tail alongside the re-enabled chain", blocksBypass: 4},
+			{name: "should not run the fading chain alongside the re-enabled chain", blocksBypass: 1},
+			{name: "should not fade a chain into itself when toggled twice between callbacks", blocksBypass: 0},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := newTestChain(t, TransitionConfig{SampleRate: 48000, MaxFrames: testFrames, FadeFrames: 2 * testFrames, Spillover: true})
+				calls := 0
+				counter := &InterpretedEffect{name: "counter", enabled: true, processFn: func([]float32) { calls++ }}
+				sut.publish(newTestSnapshot(-1, []*InterpretedEffect{counter}))
+				sut.Process(filledBuffer(1))
+
+				sut.ToggleChain()
+				for range tt.blocksBypass {
+					sut.Process(filledBuffer(1))
+				}
+				sut.ToggleChain()
+				calls = 0
+				sut.Process(filledBuffer(1))
+
+				if calls != 1 {
+					t.Errorf("got %d runs per callback, want 1", calls)
+				}
+			})
+		}
+	})
+}
@@ -2,22 +2,67 @@ package effects
 
 import (
 	"reflect"
+	"sync/atomic"
+	"time"
 )
 
+const timingSmoothing = 16
+
+const timingPeakDecay = 256
+
 type InterpretedEffect struct {
-	name      string
-	enabled   bool
-	processFn func([]float32)
+	name         string
+	enabled      bool
+	source       string
+	processFn    func([]float32)
+	channelsFn   func([][]float32)
+	sampleRateFn func(int)
+	mono         [1][]float32
+
+	timer
+}
+
+type timer struct {
+	avgNs  atomic.Int64
+	peakNs atomic.Int64
 }
 
-func newInterpretedEffect(name string, enabled bool, processFn reflect.Value) *InterpretedEffect {
-	return &InterpretedEffect{
+type EffectTiming struct {
+	Name    string
+	Average time.Duration
+	Peak    time.Duration
+}
+
+func newInterpretedEffect(name string, enabled bool, source string, processFn, channelsFn, sampleRateFn reflect.Value) *InterpretedEffect {
+	e := &InterpretedEffect{
 		name:    name,
 		enabled: enabled,
-		processFn: func(samples []float32) {
-			processFn.Call([]reflect.Value{reflect.ValueOf(samples)})
-		},
+		source:  source,
+	}
+
+	if channelsFn.IsValid() {
+		e.channelsFn, _ = channelsFn.Interface().(func([][]float32))
 	}
+
+	if sampleRateFn.IsValid() {
+		e.sampleRateFn, _ = sampleRateFn.Interface().(func(int))
+	}
+
+	if processFn.IsValid() {
+		e.processFn, _ = processFn.Interface().(func([]float32))
+	}
+	if e.processFn == nil && e.channelsFn != nil {
+		e.processFn = func(samples []float32) {
+			e.mono[0] = samples
+			e.channelsFn(e.mono[:])
+		}
+	}
+
+	return e
+}
+
+func (e *InterpretedEffect) NewInstance() (*InterpretedEffect, error) {
+	return evalEffect(e.source)
 }
 
 func (e *InterpretedEffect) Name() string {
@@ -35,3 +80,47 @@ func (e *InterpretedEffect) SetEnabled(enabled bool) {
 func (e *InterpretedEffect) Process(samples []float32) {
 	e.processFn(samples)
 }
+
+func (e *InterpretedEffect) SetSampleRate(sampleRate int) {
+	if e.sampleRateFn != nil && sampleRate > 0 {
+		e.sampleRateFn(sampleRate)
+	}
+}
+
+func (e *InterpretedEffect) ChannelAware() bool {
+	return e.channelsFn != nil
+}
+
+func (e *InterpretedEffect) ProcessChannels(channels [][]float32) {
+	if e.channelsFn != nil {
+		e.channelsFn(channels)
+		return
+	}
+	e.processFn(channels[0])
+}
+
+func (e *InterpretedEffect) Timing() EffectTiming {
+	return e.timing(e.name)
+}
+
+func (t *timer) recordTiming(d time.Duration) {
+	ns := int64(d)
+
+	avg := t.avgNs.Load()
+	t.avgNs.Store(avg + (ns-avg)/timingSmoothing)
+
+	peak := t.peakNs.Load()
+	if ns > peak {
+		t.peakNs.Store(ns)
+	} else {
+		t.peakNs.Store(peak - peak/timingPeakDecay)
+	}
+}
+
+func (t *timer) timing(name string) EffectTiming {
+	return EffectTiming{
+		Name:    name,
+		Average: time.Duration(t.avgNs.Load()),
+		Peak:    time.Duration(t.peakNs.Load()),
+	}
+}
@@ -0,0 +1,56 @@
+package effects
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimer(t *testing.T) {
+	t.Run("recordTiming", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			durations   []time.Duration
+			wantAverage time.Duration
+			wantPeak    time.Duration
+		}{
+			{name: "should move the average a sixteenth toward each sample", durations: []time.Duration{1600}, wantAverage: 100, wantPeak: 1600},
+			{name: "should jump the peak to a longer callback", durations: []time.Duration{100, 2560}, wantAverage: 6 + (2560-6)/timingSmoothing, wantPeak: 2560},
+			{name: "should decay the peak by 1/256 on a shorter callback", durations: []time.Duration{2560, 0}, wantAverage: 160 - 160/timingSmoothing, wantPeak: 2550},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				var sut timer
+
+				for _, d := range tt.durations {
+					sut.recordTiming(d)
+				}
+
+				got := sut.timing("probe")
+				if got.Average != tt.wantAverage || got.Peak != tt.wantPeak {
+					t.Errorf("got average %v peak %v, want %v and %v", got.Average, got.Peak, tt.wantAverage, tt.wantPeak)
+				}
+			})
+		}
+
+		t.Run("should converge the average and decay the peak on a steady load", func(t *testing.T) {
+			var sut timer
+			sut.recordTiming(10 * time.Millisecond)
+
+			for range 2000 {
+				sut.recordTiming(time.Millisecond)
+			}
+
+			got := sut.timing("probe")
+			if got.Average < 990*time.Microsecond || got.Average > time.Millisecond {
+				t.Errorf("got average %v, want about 1ms", got.Average)
+			}
+			if got.Peak > time.Millisecond {
+				t.Errorf("got peak %v, want decayed to the steady 1ms", got.Peak)
+			}
+			if got.Name != "probe" {
+				t.Errorf("got name %q, want probe", got.Name)
+			}
+		})
+	})
+}
@@ -25,11 +25,33 @@ func (r *EffectRegistry) GetEffect(name string) *InterpretedEffect {
 	return r.effects[name]
 }
 
+func (r *EffectRegistry) HasScript(name string) bool {
+	return r.effects[name] != nil
+}
+
+func (r *EffectRegistry) Has(name string) bool {
+	_, builtin := builtins[name]
+	return builtin || r.HasScript(name)
+}
+
+func (r *EffectRegistry) NewEffect(name string) (*InterpretedEffect, error) {
+	proto := r.effects[name]
+	if proto == nil {
+		return nil, errs.Wrap(errs.ErrEffectsNotFound, name)
+	}
+	return proto.NewInstance()
+}
+
 func (r *EffectRegistry) GetAvailableEffectNames() []string {
-	names := make([]string, 0, len(r.effects))
+	names := make([]string, 0, len(r.effects)+len(builtins))
 	for name := range r.effects {
 		names = append(names, name)
 	}
+	for name := range builtins {
+		if !r.HasScript(name) {
+			names = append(names, name)
+		}
+	}
 	sort.Strings(names)
 	return names
 }
@@ -85,12 +107,16 @@ func loadEffect(filePath string) (Effect, error) {
 		return nil, errs.Wrap(errs.ErrEffectsReadFile, err)
 	}
 
+	return evalEffect(string(code))
+}
+
+func evalEffect(code string) (*InterpretedEffect, error) {
 	i := interp.New(interp.Options{})
 	if err := i.Use(stdlib.Symbols); err != nil {
 		return nil, errs.Wrap(errs.ErrEffectsStdlib, err)
 	}
 
-	_, err = i.Eval(string(code))
+	_, err := i.Eval(code)
 	if err != nil {
 		return nil, errs.Wrap(errs.ErrEffectsEval, err)
 	}
@@ -101,12 +127,20 @@ func loadEffect(filePath string) (Effect, error) {
 	}
 	name := nameVal.Interface().(string)
 
+	channelsVal, channelsErr := i.Eval("effects.ProcessChannels")
+
 	processVal, err := i.Eval("effects.Process")
-	if err != nil {
+	if err != nil && channelsErr != nil {
 		return nil, errs.Wrap(errs.ErrEffectsGetProcess, err)
 	}
 
-	return newInterpretedEffect(name, true, processVal), nil
+	sampleRateVal, _ := i.Eval("effects.SetSampleRate")
+
+	effect := newInterpretedEffect(name, true, code, processVal, channelsVal, sampleRateVal)
+	if effect.processFn == nil {
+		return nil, errs.Wrap(errs.ErrEffectsGetProcess, name)
+	}
+	return effect, nil
 }
 
 func loadEffectsFromDir(dir string) ([]Effect, error) {
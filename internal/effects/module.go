@@ -12,6 +12,7 @@ type newChainParams struct {
 
 	Logger        *logger.Logger
 	EffectsConfig *configTypes.EffectsConfig
+	AudioConfig   *configTypes.AudioConfig
 	StateConfig   *configTypes.StateConfig
 	PresetsConfig *configTypes.PresetsConfig
 }
@@ -22,5 +23,13 @@ var Module = fx.Module("effects",
 
 func newEffectsChain(p newChainParams) *Chain {
 	p.Logger.Debug("loading effects from directory", keys.PathEffectsDir(p.EffectsConfig.EffectsDir))
-	return NewChain(p.Logger, p.EffectsConfig.EffectsDir, p.StateConfig, p.PresetsConfig)
+
+	transitionCfg := TransitionConfig{
+		SampleRate: p.AudioConfig.SampleRate,
+		MaxFrames:  p.AudioConfig.FramesPerBuffer,
+		FadeFrames: p.EffectsConfig.CrossfadeMs * p.AudioConfig.SampleRate / 1000,
+		Spillover:  p.EffectsConfig.Spillover,
+	}
+
+	return NewChain(p.Logger, p.EffectsConfig.EffectsDir, p.EffectsConfig.IRDir, p.StateConfig, p.PresetsConfig, transitionCfg)
 }
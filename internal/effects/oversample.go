@@ -0,0 +1,81 @@
+package effects
+
+import (
+	"slices"
+
+	"github.com/chloyka/gorig/internal/dsp"
+)
+
+var OversampleFactors = []int{1, 2, 4, 8}
+
+func ValidOversample(factor int) bool {
+	return factor > 1 && slices.Contains(OversampleFactors, factor)
+}
+
+type oversampling struct {
+	samplers [dsp.MaxChannels]*dsp.Oversampler
+	high     *dsp.Buffer
+	dry      *dsp.Buffer
+	chunk    dsp.Buffer
+	base     *ProcessContext
+	ctx      ProcessContext
+}
+
+func newOversampling(factor int, base *ProcessContext, maxFrames int) *oversampling {
+	o := &oversampling{
+		high: dsp.NewBuffer(dsp.MaxChannels, maxFrames*factor),
+		dry:  dsp.NewBuffer(dsp.MaxChannels, maxFrames*factor),
+		base: base,
+		ctx:  ProcessContext{SampleRate: base.SampleRate * factor},
+	}
+	for ch := range o.samplers {
+		o.samplers[ch] = dsp.NewOversampler(factor, maxFrames)
+	}
+	return o
+}
+
+func (o *oversampling) factor() int {
+	return o.samplers[0].Factor()
+}
+
+func (o *oversampling) maxFrames() int {
+	return o.samplers[0].MaxFrames()
+}
+
+func (o *oversampling) latencyFrames() int {
+	return o.samplers[0].LatencyFrames()
+}
+
+func (o *oversampling) up(buf *dsp.Buffer, offset int) *dsp.Buffer {
+	factor := o.factor()
+	frames := buf.Frames() * factor
+
+	o.high.Resize(buf.Channels(), frames)
+	for ch := 0; ch < buf.Channels(); ch++ {
+		o.samplers[ch].Up(o.high.Channel(ch), buf.Channel(ch))
+	}
+
+	o.ctx.BPM = o.base.BPM
+	o.ctx.Onset = o.base.Onset && offset == 0
+	o.ctx.Dry = nil
+	if dry := o.base.Dry; len(dry) > 0 {
+		o.dry.Resize(len(dry), frames)
+		for ch := 0; ch < o.dry.Channels(); ch++ {
+			held := o.dry.Channel(ch)
+			for i, s := range dry[ch][offset : offset+buf.Frames()] {
+				for p := 0; p < factor; p++ {
+					held[i*factor+p] = s
+				}
+			}
+		}
+		o.ctx.Dry = o.dry.Views()
+	}
+
+	return o.high
+}
+
+func (o *oversampling) down(buf *dsp.Buffer) {
+	for ch := 0; ch < buf.Channels(); ch++ {
+		o.samplers[ch].Down(buf.Channel(ch), o.high.Channel(ch))
+	}
+}
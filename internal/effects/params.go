@@ -0,0 +1,74 @@
+package effects
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+type Param struct {
+	Name    string
+	Min     float64
+	Max     float64
+	Default float64
+	Step    float64
+	Unit    string
+	Labels  []string
+	Log     bool
+}
+
+func (p Param) Clamp(v float64) float64 {
+	return max(p.Min, min(v, p.Max))
+}
+
+func (p Param) Stepped(v, steps float64) float64 {
+	if p.Log {
+		return p.Clamp(v * math.Exp2(steps*p.Step))
+	}
+	return p.Clamp(v + steps*p.Step)
+}
+
+func (p Param) Label(v float64) (string, bool) {
+	i := int(math.Round(v - p.Min))
+	if i < 0 || i >= len(p.Labels) {
+		return "", false
+	}
+	return p.Labels[i], true
+}
+
+type ParamValues struct {
+	defs   []Param
+	values []atomic.Uint64
+}
+
+func NewParamValues(defs []Param, overrides map[string]float64) *ParamValues {
+	p := &ParamValues{
+		defs:   defs,
+		values: make([]atomic.Uint64, len(defs)),
+	}
+	for i, def := range defs {
+		v, ok := overrides[def.Name]
+		if !ok {
+			v = def.Default
+		}
+		p.values[i].Store(math.Float64bits(def.Clamp(v)))
+	}
+	return p
+}
+
+func (p *ParamValues) Get(i int) float64 {
+	return math.Float64frombits(p.values[i].Load())
+}
+
+func (p *ParamValues) Set(name string, v float64) bool {
+	for i, def := range p.defs {
+		if def.Name == name {
+			p.values[i].Store(math.Float64bits(def.Clamp(v)))
+			return true
+		}
+	}
+	return false
+}
+
+func (p *ParamValues) Defs() []Param {
+	return p.defs
+}
@@ -0,0 +1,152 @@
+package effects
+
+import (
+	"time"
+
+	"github.com/chloyka/gorig/internal/dsp"
+)
+
+type stageEffect interface {
+	Name() string
+	ChannelAware() bool
+	Process(samples []float32)
+	ProcessChannels(channels [][]float32)
+	Timing() EffectTiming
+	recordTiming(d time.Duration)
+}
+
+type chainStage struct {
+	instances    []stageEffect
+	slot         int
+	oversampling *oversampling
+}
+
+type chainSnapshot struct {
+	stages   []chainStage
+	failures []stageFailure
+	split    int
+	enabled  bool
+}
+
+type stageFailure struct {
+	slot   int
+	name   string
+	stereo bool
+	err    error
+}
+
+func (s *chainSnapshot) effective() *chainSnapshot {
+	if !s.enabled {
+		return nil
+	}
+	return s
+}
+
+func (s *chainSnapshot) withEnabled(enabled bool) *chainSnapshot {
+	next := *s
+	next.enabled = enabled
+	return &next
+}
+
+func (s *chainSnapshot) shares(other *chainSnapshot) bool {
+	return s != nil && other != nil && len(s.stages) > 0 && len(other.stages) > 0 && &s.stages[0] == &other.stages[0]
+}
+
+func (s *chainSnapshot) process(buf *dsp.Buffer) {
+	for i := range s.stages {
+		if i == s.split {
+			buf.SplitToStereo()
+		}
+		s.stages[i].process(buf)
+	}
+}
+
+func (s chainStage) process(buf *dsp.Buffer) {
+	o := s.oversampling
+	if o == nil {
+		s.run(buf)
+		return
+	}
+
+	for from := 0; from < buf.Frames(); from += o.maxFrames() {
+		buf.Slice(&o.chunk, from, min(from+o.maxFrames(), buf.Frames()))
+		s.run(o.up(&o.chunk, from))
+		o.down(&o.chunk)
+	}
+}
+
+func (s chainStage) run(buf *dsp.Buffer) {
+	first := s.instances[0]
+
+	if first.ChannelAware() {
+		start := time.Now()
+		first.ProcessChannels(buf.Views())
+		first.recordTiming(time.Since(start))
+		return
+	}
+
+	for ch := 0; ch < buf.Channels() && ch < len(s.instances); ch++ {
+		effect := s.instances[ch]
+		start := time.Now()
+		effect.Process(buf.Channel(ch))
+		effect.recordTiming(time.Since(start))
+	}
+}
+
+func (s chainStage) name() string {
+	return s.instances[0].Name()
+}
+
+func (s chainStage) timing() EffectTiming {
+	timing := EffectTiming{Name: s.name()}
+	for _, effect := range s.instances {
+		t := effect.Timing()
+		timing.Average += t.Average
+		timing.Peak = max(timing.Peak, t.Peak)
+	}
+	return timing
+}
+
+func (s chainStage) oversample() int {
+	if s.oversampling == nil {
+		return 1
+	}
+	return s.oversampling.factor()
+}
+
+func (s chainStage) latencyFrames() int {
+	latency := 0
+	if effect, ok := s.builtin(); ok {
+		latency = effect.LatencyFrames()
+	}
+
+	if s.oversampling == nil {
+		return latency
+	}
+	factor := s.oversampling.factor()
+	return (latency+factor-1)/factor + s.oversampling.latencyFrames()
+}
+
+func (s chainStage) builtin() (*BuiltinEffect, bool) {
+	effect, ok := s.instances[0].(*BuiltinEffect)
+	return effect, ok
+}
+
+func (s *chainSnapshot) latencyFrames() int {
+	if s == nil {
+		return 0
+	}
+
+	latency := 0
+	for _, stage := range s.stages {
+		latency += stage.latencyFrames()
+	}
+	return latency
+}
+
+func runChain(snap *chainSnapshot, buf *dsp.Buffer) {
+	if snap == nil {
+		return
+	}
+	snap.process(buf)
+}
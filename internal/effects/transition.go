@@ -0,0 +1,211 @@
+package effects
+
+import (
+	"math"
+
+	"github.com/chloyka/gorig/internal/dsp"
+)
+
+const (
+	maxSpilloverTails = 4
+
+	tailSilenceThreshold = 1e-4
+
+	tailSilenceSeconds = 0.5
+
+	tailMaxSeconds = 10
+)
+
+type TransitionConfig struct {
+	SampleRate int
+	MaxFrames  int
+	FadeFrames int
+	Spillover  bool
+}
+
+type spilloverTail struct {
+	chain  *chainSnapshot
+	silent int
+	age    int
+}
+
+type transition struct {
+	cfg TransitionConfig
+
+	from   *chainSnapshot
+	fading bool
+	pos    int
+
+	dry   *dsp.Buffer
+	old   *dsp.Buffer
+	tails []spilloverTail
+}
+
+func newTransition(cfg TransitionConfig) *transition {
+	return &transition{
+		cfg:   cfg,
+		dry:   dsp.NewBuffer(dsp.MaxChannels, cfg.MaxFrames),
+		old:   dsp.NewBuffer(dsp.MaxChannels, cfg.MaxFrames),
+		tails: make([]spilloverTail, 0, maxSpilloverTails),
+	}
+}
+
+func (t *transition) start(from, to *chainSnapshot) {
+	if t.fading {
+		if t.from.shares(to) {
+			t.from = nil
+		} else {
+			t.retire(t.from)
+		}
+	}
+	t.dropShared(to)
+
+	if t.cfg.FadeFrames <= 0 || from.shares(to) {
+		t.fading = false
+		t.from = nil
+		return
+	}
+
+	t.from = from
+	t.fading = true
+	t.pos = 0
+}
+
+func (t *transition) dropShared(to *chainSnapshot) {
+	for i := 0; i < len(t.tails); {
+		if t.tails[i].chain.shares(to) {
+			t.tails = append(t.tails[:i], t.tails[i+1:]...)
+			continue
+		}
+		i++
+	}
+}
+
+func (t *transition) retire(chain *chainSnapshot) {
+	if !t.cfg.Spillover || chain == nil || len(chain.stages) == 0 {
+		return
+	}
+
+	if len(t.tails) == cap(t.tails) {
+		copy(t.tails, t.tails[1:])
+		t.tails = t.tails[:len(t.tails)-1]
+	}
+	t.tails = append(t.tails, spilloverTail{chain: chain})
+}
+
+func (t *transition) process(current *chainSnapshot, buf *dsp.Buffer) {
+	if buf.Frames() == 0 {
+		return
+	}
+
+	if !t.fading && len(t.tails) == 0 {
+		runChain(current, buf)
+		return
+	}
+
+	t.dry.CopyFrom(buf)
+
+	runChain(current, buf)
+
+	if t.fading {
+		t.crossfade(buf)
+	}
+
+	if len(t.tails) > 0 {
+		t.mixTails(buf)
+	}
+}
+
+func matchChannels(a, b *dsp.Buffer) {
+	if a.Channels() < b.Channels() {
+		a.SplitToStereo()
+	}
+	if b.Channels() < a.Channels() {
+		b.SplitToStereo()
+	}
+}
+
+func (t *transition) crossfade(buf *dsp.Buffer) {
+	old := t.old
+	old.CopyFrom(t.dry)
+
+	fadeLen := float64(t.cfg.FadeFrames)
+	frames := buf.Frames()
+
+	if t.cfg.Spillover {
+		for ch := 0; ch < old.Channels(); ch++ {
+			samples := old.Channel(ch)
+			for f := range samples {
+				g := math.Min(float64(t.pos+f)/fadeLen, 1)
+				samples[f] *= float32(1 - g)
+			}
+		}
+	}
+
+	runChain(t.from, old)
+	matchChannels(buf, old)
+
+	for ch := 0; ch < buf.Channels(); ch++ {
+		samples := buf.Channel(ch)
+		oldSamples := old.Channel(ch)
+		for f := range samples {
+			g := math.Min(float64(t.pos+f)/fadeLen, 1)
+			if t.cfg.Spillover {
+				samples[f] = samples[f]*float32(g) + oldSamples[f]
+			} else {
+				angle := g * math.Pi / 2
+				samples[f] = samples[f]*float32(math.Sin(angle)) + oldSamples[f]*float32(math.Cos(angle))
+			}
+		}
+	}
+
+	t.pos += frames
+	if t.pos >= t.cfg.FadeFrames {
+		t.retire(t.from)
+		t.from = nil
+		t.fading = false
+	}
+}
+
+func (t *transition) mixTails(buf *dsp.Buffer) {
+	tailBuf := t.old
+	frames := buf.Frames()
+
+	silenceLimit := int(tailSilenceSeconds * float64(t.cfg.SampleRate))
+	ageLimit := tailMaxSeconds * t.cfg.SampleRate
+
+	for i := 0; i < len(t.tails); {
+		tail := &t.tails[i]
+
+		tailBuf.Resize(t.dry.Channels(), frames)
+		tailBuf.Clear()
+		runChain(tail.chain, tailBuf)
+		matchChannels(buf, tailBuf)
+
+		var peak float32
+		for ch := 0; ch < buf.Channels(); ch++ {
+			samples := buf.Channel(ch)
+			for f, s := range tailBuf.Channel(ch) {
+				samples[f] += s
+				if s > peak {
+					peak = s
+				} else if -s > peak {
+					peak = -s
+				}
+			}
+		}
+
+		tail.age += frames
+		if peak < tailSilenceThreshold {
+			tail.silent += frames
+		} else {
+			tail.silent = 0
+		}
+
+		if tail.silent >= silenceLimit || tail.age >= ageLimit {
+			t.tails = append(t.tails[:i], t.tails[i+1:]...)
+			continue
+		}
+		i++
+	}
+}
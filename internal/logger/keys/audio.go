@@ -8,4 +8,30 @@ var (
 	AudioInputLatencyMs = Float64("audio.input_latency_ms")
 
 	AudioOutputLatencyMs = Float64("audio.output_latency_ms")
+
+	AudioLoadPercent = Float64("audio.load_pct")
+
+	AudioPeakLoadPercent = Float64("audio.peak_load_pct")
+
+	AudioXruns = Int("audio.xruns")
+
+	AudioGainDb = Float64("audio.gain_db")
+
+	AudioChannel = Int("audio.channel")
+
+	AudioInputChannels = Int("audio.input_channels")
+
+	AudioOutputChannels = Int("audio.output_channels")
+
+	AudioTargetLatencyMs = Float64("audio.target_latency_ms")
+
+	AudioLatencySamples = Int("audio.latency_samples")
+
+	AudioLatencyMs = Float64("audio.latency_ms")
+
+	AudioJitterMs = Float64("audio.jitter_ms")
+
+	AudioRequestedSampleRate = Int("audio.requested_sample_rate")
+
+	AudioRequestedFramesPerBuffer = Int("audio.requested_frames_per_buffer")
 )
@@ -8,4 +8,8 @@ var (
 	EffectEnabled = Bool("effect.enabled")
 
 	EffectHasEnabled = Bool("effect.has_enabled")
+
+	EffectAvgUs = Float64("effect.avg_us")
+
+	EffectPeakUs = Float64("effect.peak_us")
 )
@@ -8,4 +8,6 @@ var (
 	PathConfigLoaded = String("path.config_loaded")
 
 	PathEffectsDir = String("path.effects_dir")
+
+	PathPattern = String("path.pattern")
 )
@@ -0,0 +1,9 @@
+package keys
+
+var (
+	PatternBars = Int("pattern.bars")
+
+	PatternHits = Int("pattern.hits")
+
+	PatternBPM = Float64("pattern.bpm")
+)
@@ -0,0 +1,5 @@
+package keys
+
+var (
+	RhythmBoundary = String("rhythm.boundary")
+)
@@ -3,6 +3,8 @@ package onset
 import (
 	"math"
 	"sync"
+
+	"github.com/chloyka/gorig/internal/dsp"
 )
 
 type Event struct {
@@ -18,6 +20,8 @@ type Detector struct {
 	attackCoeff   float32
 	releaseCoeff  float32
 	minIntervalMs float32
+	attackMs      float32
+	releaseMs     float32
 	sampleRate    float32
 	samplesPerMs  float32
 
@@ -60,25 +64,35 @@ func DefaultConfig(sampleRate float32) Config {
 }
 
 func NewDetector(cfg Config) *Detector {
-	samplesPerMs := cfg.SampleRate / 1000.0
-
-	attackCoeff := float32(1.0 - math.Exp(-1.0/float64(cfg.AttackMs*samplesPerMs)))
-	releaseCoeff := float32(1.0 - math.Exp(-1.0/float64(cfg.ReleaseMs*samplesPerMs)))
-
-	return &Detector{
-		threshold:          cfg.Threshold,
-		minEnergy:          cfg.MinEnergy,
-		attackCoeff:        attackCoeff,
-		releaseCoeff:       releaseCoeff,
-		minIntervalMs:      cfg.MinIntervalMs,
-		sampleRate:         cfg.SampleRate,
-		samplesPerMs:       samplesPerMs,
-		minIntervalSamples: int64(cfg.MinIntervalMs * samplesPerMs),
-		onsetChan:          make(chan Event, cfg.BufferSize),
-		enabled:            true,
-		baseline:           0.01,
-		wasLow:             true,
+	d := &Detector{
+		threshold:     cfg.Threshold,
+		minEnergy:     cfg.MinEnergy,
+		minIntervalMs: cfg.MinIntervalMs,
+		attackMs:      cfg.AttackMs,
+		releaseMs:     cfg.ReleaseMs,
+		onsetChan:     make(chan Event, cfg.BufferSize),
+		enabled:       true,
+		baseline:      0.01,
+		wasLow:        true,
 	}
+	d.applySampleRate(cfg.SampleRate)
+	return d
+}
+
+func (d *Detector) SetSampleRate(sampleRate float32) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.applySampleRate(sampleRate)
+}
+
+func (d *Detector) applySampleRate(sampleRate float32) {
+	samplesPerMs := sampleRate / 1000.0
+
+	d.sampleRate = sampleRate
+	d.samplesPerMs = samplesPerMs
+	d.attackCoeff = dsp.SmoothingCoeff(float64(d.attackMs), float64(sampleRate))
+	d.releaseCoeff = dsp.SmoothingCoeff(float64(d.releaseMs), float64(sampleRate))
+	d.minIntervalSamples = int64(d.minIntervalMs * samplesPerMs)
 }
 
 func (d *Detector) Process(samples []float32) bool {
@@ -0,0 +1,106 @@
+package pattern
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/chloyka/gorig/internal/rhythm"
+)
+
+const (
+	ticksPerQuarter = 480
+
+	drumChannel = 9
+
+	noteLengthTicks = ticksPerQuarter / 8
+)
+
+type midiEvent struct {
+	tick uint32
+	data []byte
+}
+
+func WriteSMF(w io.Writer, hits []Hit, note uint8, bpm float64, lengthBeats float64) error {
+	var track bytes.Buffer
+
+	microsPerQuarter := uint32(math.Round(60_000_000 / bpm))
+	events := []midiEvent{
+		{tick: 0, data: []byte{0xFF, 0x58, 0x04, rhythm.BeatsPerBar, 0x02, 0x18, 0x08}},
+		{tick: 0, data: []byte{0xFF, 0x51, 0x03, byte(microsPerQuarter >> 16), byte(microsPerQuarter >> 8), byte(microsPerQuarter)}},
+	}
+
+	sorted := append([]Hit(nil), hits...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Beat < sorted[j].Beat })
+
+	for i, hit := range sorted {
+		on := beatToTick(hit.Beat)
+		length := uint32(noteLengthTicks)
+		if i+1 < len(sorted) {
+			if gap := beatToTick(sorted[i+1].Beat) - on; gap < length {
+				length = max(1, gap)
+			}
+		}
+
+		events = append(events,
+			midiEvent{tick: on, data: []byte{0x90 | drumChannel, note & 0x7F, hit.Velocity & 0x7F}},
+			midiEvent{tick: on + length, data: []byte{0x80 | drumChannel, note & 0x7F, 0}},
+		)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].tick != events[j].tick {
+			return events[i].tick < events[j].tick
+		}
+		return isNoteOff(events[i].data) && !isNoteOff(events[j].data)
+	})
+
+	endTick := beatToTick(lengthBeats)
+	var last uint32
+	for _, ev := range events {
+		writeVarLen(&track, ev.tick-last)
+		track.Write(ev.data)
+		last = ev.tick
+		endTick = max(endTick, ev.tick)
+	}
+
+	writeVarLen(&track, endTick-last)
+	track.Write([]byte{0xFF, 0x2F, 0x00})
+
+	var out bytes.Buffer
+	out.WriteString("MThd")
+	_ = binary.Write(&out, binary.BigEndian, uint32(6))
+	_ = binary.Write(&out, binary.BigEndian, uint16(0))
+	_ = binary.Write(&out, binary.BigEndian, uint16(1))
+	_ = binary.Write(&out, binary.BigEndian, uint16(ticksPerQuarter))
+
+	out.WriteString("MTrk")
+	_ = binary.Write(&out, binary.BigEndian, uint32(track.Len()))
+	out.Write(track.Bytes())
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+func beatToTick(beat float64) uint32 {
+	return uint32(math.Round(beat * ticksPerQuarter))
+}
+
+func isNoteOff(data []byte) bool {
+	return len(data) > 0 && data[0]&0xF0 == 0x80
+}
+
+func writeVarLen(buf *bytes.Buffer, value uint32) {
+	var tmp [4]byte
+	n := 0
+	tmp[n] = byte(value & 0x7F)
+	for value >>= 7; value > 0; value >>= 7 {
+		n++
+		tmp[n] = byte(value&0x7F) | 0x80
+	}
+	for ; n >= 0; n-- {
+		buf.WriteByte(tmp[n])
+	}
+}
@@ -0,0 +1,56 @@
+package pattern
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteSMF(t *testing.T) {
+	t.Run("should write type-0 header with a single track", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		err := WriteSMF(&buf, []Hit{{Beat: 0, Velocity: 100}}, 36, 120, 4)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		got := buf.Bytes()
+		want := []byte{'M', 'T', 'h', 'd', 0, 0, 0, 6, 0, 0, 0, 1, 0x01, 0xE0}
+		if !bytes.Equal(got[:len(want)], want) {
+			t.Errorf("got header % x, want % x", got[:len(want)], want)
+		}
+	})
+
+	t.Run("should encode tempo from bpm", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		_ = WriteSMF(&buf, nil, 36, 120, 4)
+
+		want := []byte{0xFF, 0x51, 0x03, 0x07, 0xA1, 0x20}
+		if !bytes.Contains(buf.Bytes(), want) {
+			t.Errorf("tempo event % x not found", want)
+		}
+	})
+
+	t.Run("should place note-on at hit tick with velocity", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		_ = WriteSMF(&buf, []Hit{{Beat: 1, Velocity: 90}}, 36, 120, 4)
+
+		want := []byte{0x83, 0x60, 0x99, 36, 90}
+		if !bytes.Contains(buf.Bytes(), want) {
+			t.Errorf("note-on % x not found in % x", want, buf.Bytes())
+		}
+	})
+
+	t.Run("should end track with end-of-track meta event", func(t *testing.T) {
+		var buf bytes.Buffer
+
+		_ = WriteSMF(&buf, []Hit{{Beat: 0, Velocity: 64}}, 36, 120, 4)
+
+		got := buf.Bytes()
+		if !bytes.HasSuffix(got, []byte{0xFF, 0x2F, 0x00}) {
+			t.Errorf("got suffix % x, want end-of-track", got[len(got)-3:])
+		}
+	})
+}
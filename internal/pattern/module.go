@@ -0,0 +1,16 @@
+package pattern
+
+import (
+	configTypes "github.com/chloyka/gorig/internal/config/types"
+	"github.com/chloyka/gorig/internal/logger"
+	"github.com/chloyka/gorig/internal/rhythm"
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("pattern",
+	fx.Provide(NewRecorderFromConfig),
+)
+
+func NewRecorderFromConfig(log *logger.Logger, rhythmEngine *rhythm.Engine, cfg *configTypes.RecorderConfig) *Recorder {
+	return NewRecorder(log, rhythmEngine, cfg.PatternsDir, cfg.Bars, cfg.MidiNote, cfg.SetBars)
+}
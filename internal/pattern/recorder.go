@@ -0,0 +1,281 @@
+package pattern
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/chloyka/gorig/internal/logger"
+	"github.com/chloyka/gorig/internal/logger/keys"
+	"github.com/chloyka/gorig/internal/rhythm"
+	errs "github.com/chloyka/gorig/utils/errors"
+)
+
+const MaxBars = 16
+
+type State int
+
+const (
+	StateIdle State = iota
+	StateArmed
+	StateRecording
+	StateDone
+)
+
+func (s State) String() string {
+	switch s {
+	case StateArmed:
+		return "armed"
+	case StateRecording:
+		return "recording"
+	case StateDone:
+		return "done"
+	default:
+		return "idle"
+	}
+}
+
+type Hit struct {
+	Beat      float64
+	SlotIndex int
+	Velocity  uint8
+}
+
+type Pattern struct {
+	Bars          int
+	Grid          rhythm.Grid
+	SlotPositions []float64
+	Hits          []Hit
+}
+
+type beatSource interface {
+	GetBeatCount() int64
+	GetBPM() float64
+	GetGrid() rhythm.Grid
+	GetSlotPositions(fromBeat int64, beats int) []float64
+}
+
+type Recorder struct {
+	mu sync.Mutex
+
+	logger       *logger.Logger
+	rhythmEngine beatSource
+	patternsDir  string
+	note         uint8
+
+	onBarsChanged func(bars int)
+
+	bars      int
+	startBeat int64
+	endBeat   int64
+	armed     bool
+	pattern   Pattern
+}
+
+func NewRecorder(
+	log *logger.Logger,
+	rhythmEngine beatSource,
+	patternsDir string,
+	bars int,
+	note int,
+	onBarsChanged func(bars int),
+) *Recorder {
+	if bars < 1 {
+		bars = 1
+	}
+
+	return &Recorder{
+		logger:        log,
+		rhythmEngine:  rhythmEngine,
+		patternsDir:   patternsDir,
+		note:          uint8(max(0, min(127, note))),
+		bars:          bars,
+		onBarsChanged: onBarsChanged,
+	}
+}
+
+func (r *Recorder) Bars() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.bars
+}
+
+func (r *Recorder) SetBars(bars int) {
+	r.mu.Lock()
+	if r.armed || bars < 1 || bars > MaxBars {
+		r.mu.Unlock()
+		return
+	}
+	r.bars = bars
+	r.mu.Unlock()
+
+	if r.onBarsChanged != nil {
+		r.onBarsChanged(bars)
+	}
+}
+
+func (r *Recorder) Arm() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	beat := r.rhythmEngine.GetBeatCount()
+	r.startBeat = (beat/rhythm.BeatsPerBar + 1) * rhythm.BeatsPerBar
+	r.endBeat = r.startBeat + int64(r.bars*rhythm.BeatsPerBar)
+	r.armed = true
+	r.pattern = Pattern{
+		Bars:          r.bars,
+		Grid:          r.rhythmEngine.GetGrid(),
+		SlotPositions: r.rhythmEngine.GetSlotPositions(r.startBeat, r.bars*rhythm.BeatsPerBar),
+	}
+
+	r.logger.Info("pattern recording armed", keys.PatternBars(r.bars))
+}
+
+func (r *Recorder) Stop() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.armed {
+		return
+	}
+
+	beat := r.rhythmEngine.GetBeatCount()
+	if beat < r.startBeat {
+		r.armed = false
+		r.pattern = Pattern{}
+		r.logger.Info("pattern recording cancelled")
+		return
+	}
+
+	if beat < r.endBeat {
+		bars := int((beat-r.startBeat)/rhythm.BeatsPerBar) + 1
+		r.endBeat = r.startBeat + int64(bars*rhythm.BeatsPerBar)
+		r.pattern.Bars = bars
+		r.pattern.SlotPositions = trimPositions(r.pattern.SlotPositions, float64(bars*rhythm.BeatsPerBar))
+	}
+	r.armed = false
+
+	r.logger.Info("pattern recording stopped",
+		keys.PatternBars(r.pattern.Bars),
+		keys.PatternHits(len(r.pattern.Hits)),
+	)
+}
+
+func (r *Recorder) Record(q rhythm.QuantizedOnset) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.armed {
+		return
+	}
+
+	if q.Beat < float64(r.startBeat) || q.Beat >= float64(r.endBeat) {
+		return
+	}
+
+	r.pattern.Hits = append(r.pattern.Hits, Hit{
+		Beat:      q.Beat - float64(r.startBeat),
+		SlotIndex: q.SlotIndex,
+		Velocity:  energyToVelocity(q.OriginalEvent.Energy),
+	})
+}
+
+func (r *Recorder) State() State {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stateLocked()
+}
+
+func (r *Recorder) stateLocked() State {
+	if !r.armed {
+		if r.pattern.Bars > 0 {
+			return StateDone
+		}
+		return StateIdle
+	}
+
+	beat := r.rhythmEngine.GetBeatCount()
+	switch {
+	case beat < r.startBeat:
+		return StateArmed
+	case beat < r.endBeat:
+		return StateRecording
+	default:
+		r.armed = false
+		r.logger.Info("pattern recording finished",
+			keys.PatternBars(r.pattern.Bars),
+			keys.PatternHits(len(r.pattern.Hits)),
+		)
+		return StateDone
+	}
+}
+
+func (r *Recorder) BeatsUntilStart() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.armed {
+		return 0
+	}
+	return max(0, r.startBeat-r.rhythmEngine.GetBeatCount())
+}
+
+func (r *Recorder) Pattern() Pattern {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p := r.pattern
+	p.SlotPositions = append([]float64(nil), r.pattern.SlotPositions...)
+	p.Hits = append([]Hit(nil), r.pattern.Hits...)
+	return p
+}
+
+func (r *Recorder) Export() (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stateLocked() != StateDone || len(r.pattern.Hits) == 0 {
+		return "", errs.ErrPatternEmpty
+	}
+
+	if err := os.MkdirAll(r.patternsDir, 0755); err != nil {
+		return "", errs.Wrap(errs.ErrPatternCreateDir, err)
+	}
+
+	bpm := r.rhythmEngine.GetBPM()
+	path := filepath.Join(r.patternsDir, "pattern-"+time.Now().Format("20060102-150405")+".mid")
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", errs.Wrap(errs.ErrPatternWrite, err)
+	}
+	defer file.Close()
+
+	lengthBeats := float64(r.pattern.Bars * rhythm.BeatsPerBar)
+	if err := WriteSMF(file, r.pattern.Hits, r.note, bpm, lengthBeats); err != nil {
+		return "", errs.Wrap(errs.ErrPatternWrite, err)
+	}
+
+	r.logger.Info("pattern exported",
+		keys.PathPattern(path),
+		keys.PatternHits(len(r.pattern.Hits)),
+		keys.PatternBPM(bpm),
+	)
+
+	return path, nil
+}
+
+func energyToVelocity(energy float32) uint8 {
+	v := 1 + int(energy*126+0.5)
+	return uint8(max(1, min(127, v)))
+}
+
+func trimPositions(positions []float64, limit float64) []float64 {
+	for i, pos := range positions {
+		if pos >= limit {
+			return positions[:i]
+		}
+	}
+	return positions
+}
@@ -0,0 +1,237 @@
+package pattern
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chloyka/gorig/internal/logger"
+	"github.com/chloyka/gorig/internal/onset"
+	"github.com/chloyka/gorig/internal/rhythm"
+	errs "github.com/chloyka/gorig/utils/errors"
+	"go.uber.org/zap"
+)
+
+type fakeBeats struct {
+	beat int64
+}
+
+func (f *fakeBeats) GetBeatCount() int64 { return f.beat }
+
+func (f *fakeBeats) GetBPM() float64 { return 120 }
+
+func (f *fakeBeats) GetGrid() rhythm.Grid { return rhythm.Grid{} }
+
+func (f *fakeBeats) GetSlotPositions(fromBeat int64, beats int) []float64 {
+	positions := make([]float64, beats)
+	for i := range positions {
+		positions[i] = float64(i)
+	}
+	return positions
+}
+
+func newTestRecorder(t *testing.T, beat int64, bars int, onBarsChanged func(int)) (*Recorder, *fakeBeats) {
+	t.Helper()
+
+	beats := &fakeBeats{beat: beat}
+	log := &logger.Logger{Logger: zap.NewNop()}
+	return NewRecorder(log, beats, t.TempDir(), bars, 36, onBarsChanged), beats
+}
+
+func hitAt(beat float64) rhythm.QuantizedOnset {
+	return rhythm.QuantizedOnset{Beat: beat, OriginalEvent: onset.Event{Energy: 0.5}}
+}
+
+func TestRecorder(t *testing.T) {
+	t.Run("Arm", func(t *testing.T) {
+		t.Run("should start recording on the next bar line", func(t *testing.T) {
+			sut, _ := newTestRecorder(t, 5, 2, nil)
+
+			sut.Arm()
+
+			if got := sut.State(); got != StateArmed {
+				t.Errorf("got state %v, want armed", got)
+			}
+			if got := sut.BeatsUntilStart(); got != 3 {
+				t.Errorf("got %d beats until start, want 3", got)
+			}
+			if got := len(sut.Pattern().SlotPositions); got != 2*rhythm.BeatsPerBar {
+				t.Errorf("got %d slot positions, want %d", got, 2*rhythm.BeatsPerBar)
+			}
+		})
+	})
+
+	t.Run("Record", func(t *testing.T) {
+		t.Run("should keep hits inside the recording window relative to its start", func(t *testing.T) {
+			sut, beats := newTestRecorder(t, 3, 1, nil)
+			sut.Arm()
+			beats.beat = 5
+
+			sut.Record(hitAt(3.5))
+			sut.Record(hitAt(4))
+			sut.Record(hitAt(6.25))
+			sut.Record(hitAt(8))
+
+			got := sut.Pattern().Hits
+			if len(got) != 2 || got[0].Beat != 0 || got[1].Beat != 2.25 {
+				t.Errorf("got hits %+v, want beats 0 and 2.25", got)
+			}
+			if got[0].Velocity != energyToVelocity(0.5) {
+				t.Errorf("got velocity %d, want %d", got[0].Velocity, energyToVelocity(0.5))
+			}
+		})
+
+		t.Run("should ignore hits while not armed", func(t *testing.T) {
+			sut, _ := newTestRecorder(t, 0, 1, nil)
+
+			sut.Record(hitAt(1))
+
+			if got := sut.Pattern().Hits; len(got) != 0 {
+				t.Errorf("got hits %+v, want none", got)
+			}
+		})
+	})
+
+	t.Run("State", func(t *testing.T) {
+		tests := []struct {
+			name string
+			beat int64
+			want State
+		}{
+			{name: "should be armed before the start bar", beat: 3, want: StateArmed},
+			{name: "should be recording inside the window", beat: 6, want: StateRecording},
+			{name: "should finish on its own at the end bar", beat: 8, want: StateDone},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut, beats := newTestRecorder(t, 1, 1, nil)
+				sut.Arm()
+				beats.beat = tt.beat
+
+				got := sut.State()
+
+				if got != tt.want {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			})
+		}
+
+		t.Run("should stop accepting hits after finishing", func(t *testing.T) {
+			sut, beats := newTestRecorder(t, 1, 1, nil)
+			sut.Arm()
+			beats.beat = 8
+			sut.State()
+
+			sut.Record(hitAt(7))
+
+			if got := sut.Pattern().Hits; len(got) != 0 {
+				t.Errorf("got hits %+v, want none", got)
+			}
+		})
+	})
+
+	t.Run("Stop", func(t *testing.T) {
+		t.Run("should cancel when stopped before the start bar", func(t *testing.T) {
+			sut, _ := newTestRecorder(t, 1, 2, nil)
+			sut.Arm()
+
+			sut.Stop()
+
+			if got := sut.State(); got != StateIdle {
+				t.Errorf("got %v, want idle", got)
+			}
+		})
+
+		t.Run("should trim the pattern to the bars played so far", func(t *testing.T) {
+			sut, beats := newTestRecorder(t, 1, 4, nil)
+			sut.Arm()
+			beats.beat = 9
+			sut.Record(hitAt(9))
+
+			sut.Stop()
+
+			got := sut.Pattern()
+			if sut.State() != StateDone || got.Bars != 2 {
+				t.Errorf("got %v with %d bars, want done with 2", sut.State(), got.Bars)
+			}
+			if len(got.SlotPositions) != 2*rhythm.BeatsPerBar {
+				t.Errorf("got %d slot positions, want %d", len(got.SlotPositions), 2*rhythm.BeatsPerBar)
+			}
+		})
+	})
+
+	t.Run("SetBars", func(t *testing.T) {
+		t.Run("should notify without holding the recorder lock", func(t *testing.T) {
+			var sut *Recorder
+			got := 0
+			sut, _ = newTestRecorder(t, 0, 1, func(int) { got = sut.Bars() })
+
+			sut.SetBars(3)
+
+			if got != 3 {
+				t.Errorf("got %d bars in callback, want 3", got)
+			}
+		})
+
+		tests := []struct {
+			name  string
+			bars  int
+			armed bool
+		}{
+			{name: "should ignore zero bars", bars: 0},
+			{name: "should ignore more than the maximum bars", bars: MaxBars + 1},
+			{name: "should ignore changes while armed", bars: 3, armed: true},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				called := false
+				sut, _ := newTestRecorder(t, 0, 2, func(int) { called = true })
+				if tt.armed {
+					sut.Arm()
+				}
+
+				sut.SetBars(tt.bars)
+
+				if got := sut.Bars(); got != 2 || called {
+					t.Errorf("got %d bars (notified %v), want 2 unchanged", got, called)
+				}
+			})
+		}
+	})
+
+	t.Run("Export", func(t *testing.T) {
+		t.Run("should refuse an empty pattern", func(t *testing.T) {
+			sut, beats := newTestRecorder(t, 0, 1, nil)
+			sut.Arm()
+			beats.beat = 8
+
+			_, err := sut.Export()
+
+			if !errs.Is(err, errs.ErrPatternEmpty) {
+				t.Errorf("got %v, want %v", err, errs.ErrPatternEmpty)
+			}
+		})
+
+		t.Run("should write a MIDI file into the patterns directory", func(t *testing.T) {
+			sut, beats := newTestRecorder(t, 0, 1, nil)
+			sut.Arm()
+			beats.beat = 5
+			sut.Record(hitAt(5))
+			beats.beat = 8
+
+			got, err := sut.Export()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if filepath.Ext(got) != ".mid" {
+				t.Errorf("got %q, want a .mid file", got)
+			}
+			if _, err := os.Stat(got); err != nil {
+				t.Errorf("got %v, want exported file on disk", err)
+			}
+		})
+	})
+}
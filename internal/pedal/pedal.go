@@ -5,6 +5,7 @@ import (
 	"github.com/chloyka/gorig/internal/logger"
 	"github.com/chloyka/gorig/internal/logger/keys"
 	"github.com/chloyka/gorig/internal/preset"
+	"github.com/chloyka/gorig/internal/rhythm"
 )
 
 type State struct {
@@ -64,6 +65,25 @@ func (s *State) GetEffects() []effects.EffectInfo {
 	return s.chain.GetEffects()
 }
 
+func (s *State) EffectMeters() []effects.EffectMeter {
+	return s.chain.EffectMeters()
+}
+
+func (s *State) SetEffectParam(presetName string, slot int, name string, value float64) {
+	if presetName != s.presetManager.GetActivePresetName() {
+		return
+	}
+	s.chain.SetSlotParam(slot, name, value)
+}
+
+func (s *State) AvailableIRs() []string {
+	return s.chain.AvailableIRs()
+}
+
+func (s *State) PendingSwitch() rhythm.Boundary {
+	return s.chain.PendingBoundary()
+}
+
 func (s *State) GetPresetManager() *preset.Manager {
 	return s.presetManager
 }
@@ -25,6 +25,7 @@ func provideManager(p newManagerParams) *Manager {
 		p.PresetsConfig,
 		p.Chain.GetAvailableEffectNames,
 		p.Chain.SetPresetChain,
+		p.Chain.QueuePresetChain,
 	)
 	return m
 }
@@ -5,6 +5,8 @@ import (
 
 	configTypes "github.com/chloyka/gorig/internal/config/types"
 	"github.com/chloyka/gorig/internal/logger"
+	"github.com/chloyka/gorig/internal/logger/keys"
+	"github.com/chloyka/gorig/internal/rhythm"
 	errs "github.com/chloyka/gorig/utils/errors"
 )
 
@@ -28,6 +30,8 @@ type Manager struct {
 	getAvailableEffects func() []string
 
 	onPresetChanged func(chain []string)
+
+	onPresetQueued func(chain []string, boundary rhythm.Boundary)
 }
 
 func NewManager(
@@ -35,12 +39,14 @@ func NewManager(
 	presetsConfig *configTypes.PresetsConfig,
 	getAvailableEffects func() []string,
 	onPresetChanged func(chain []string),
+	onPresetQueued func(chain []string, boundary rhythm.Boundary),
 ) *Manager {
 	return &Manager{
 		presetsConfig:       presetsConfig,
 		logger:              logger,
 		getAvailableEffects: getAvailableEffects,
 		onPresetChanged:     onPresetChanged,
+		onPresetQueued:      onPresetQueued,
 	}
 }
 
@@ -79,6 +85,13 @@ func (m *Manager) SetActivePreset(name string) error {
 
 	m.presetsConfig.SetActivePreset(name)
 
+	boundary := rhythm.BoundaryFromString(m.presetsConfig.SwitchMode)
+	if boundary != rhythm.BoundaryNone && m.onPresetQueued != nil {
+		m.onPresetQueued(preset.EffectChain, boundary)
+		m.logger.Info("active preset queued", keys.RhythmBoundary(boundary.String()))
+		return nil
+	}
+
 	if m.onPresetChanged != nil {
 		m.onPresetChanged(preset.EffectChain)
 	}
@@ -87,6 +100,23 @@ func (m *Manager) SetActivePreset(name string) error {
 	return nil
 }
 
+func (m *Manager) GetSwitchMode() rhythm.Boundary {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return rhythm.BoundaryFromString(m.presetsConfig.SwitchMode)
+}
+
+func (m *Manager) CycleSwitchMode() rhythm.Boundary {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	next := rhythm.BoundaryFromString(m.presetsConfig.SwitchMode).Next()
+	m.presetsConfig.SetSwitchMode(next.String())
+
+	m.logger.Info("preset switch mode changed", keys.RhythmBoundary(next.String()))
+	return next
+}
+
 func (m *Manager) CreatePreset(name string, chain []string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -201,6 +231,21 @@ func (m *Manager) UpdatePresetChain(presetName string, chain []string) error {
 	return nil
 }
 
+func (m *Manager) UpdatePresetLayout(presetName string, chain []string, split *int, slots []configTypes.SlotSettings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.presetsConfig.UpdatePresetLayout(presetName, chain, split, slots) {
+		return errs.Wrap(errs.ErrPresetNotFound, presetName)
+	}
+
+	if presetName == m.presetsConfig.ActivePreset && m.onPresetChanged != nil {
+		m.onPresetChanged(chain)
+	}
+
+	return nil
+}
+
 func (m *Manager) AddEffectToPreset(presetName, effectName string, position int) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -13,6 +13,7 @@ type Engine struct {
 	tempo *TempoState
 
 	totalSamples int64
+	bufferStart  int64
 	currentSlot  int64
 
 	onsetEvents <-chan onset.Event
@@ -22,15 +23,17 @@ type Engine struct {
 
 	quantizedChan chan QuantizedOnset
 
-	onStateChange func(bpm float64, subdivision int)
+	onStateChange func(bpm float64, grid Grid)
 }
 
 type EngineConfig struct {
 	SampleRate    float32
 	InitialBPM    float64
 	Subdivision   Subdivision
+	Feel          Feel
+	Swing         int
 	OnsetEvents   <-chan onset.Event
-	OnStateChange func(bpm float64, subdivision int)
+	OnStateChange func(bpm float64, grid Grid)
 }
 
 func NewEngine(cfg EngineConfig) *Engine {
@@ -45,8 +48,10 @@ func NewEngine(cfg EngineConfig) *Engine {
 		sub = Sub8
 	}
 
+	grid := Grid{Subdivision: sub, Feel: cfg.Feel, Swing: cfg.Swing}
+
 	e := &Engine{
-		tempo:         NewTempoState(bpm, sub, cfg.SampleRate),
+		tempo:         NewTempoStateWithGrid(bpm, grid, cfg.SampleRate),
 		onsetEvents:   cfg.OnsetEvents,
 		quantizedChan: make(chan QuantizedOnset, 16),
 		onStateChange: cfg.OnStateChange,
@@ -63,17 +68,16 @@ func (e *Engine) ProcessBuffer(bufferSize int) *QuantizedOnset {
 	e.drainOnsetEvents()
 
 	oldSlot := e.currentSlot
+	e.bufferStart = e.totalSamples
 	e.totalSamples += int64(bufferSize)
-
-	if e.tempo.SamplesPerSlot > 0 {
-		e.currentSlot = e.totalSamples / e.tempo.SamplesPerSlot
-	}
+	e.currentSlot = e.tempo.SlotAt(e.totalSamples)
 
 	if e.currentSlot > oldSlot && e.pendingOnset != nil && e.currentSlot > e.lastSlotFired {
 		result := &QuantizedOnset{
 			OriginalEvent: *e.pendingOnset,
-			SlotIndex:     int(e.currentSlot % int64(e.tempo.Subdivision)),
+			SlotIndex:     e.tempo.SlotInCycle(e.currentSlot),
 			BeatPosition:  e.getBeatPositionLocked(),
+			Beat:          e.slotBeatLocked(e.currentSlot),
 			WasQueued:     true,
 		}
 
@@ -130,6 +134,66 @@ func (e *Engine) drainOnsetEvents() {
 	}
 }
 
+func (e *Engine) SetSampleRate(sampleRate float32) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	oldRate := e.tempo.SampleRate
+	if sampleRate <= 0 || sampleRate == oldRate {
+		return
+	}
+
+	ratio := float64(sampleRate) / float64(oldRate)
+	e.totalSamples = int64(float64(e.totalSamples) * ratio)
+	e.bufferStart = int64(float64(e.bufferStart) * ratio)
+
+	e.tempo.SetSampleRate(sampleRate)
+	e.currentSlot = e.tempo.SlotAt(e.totalSamples)
+	e.lastSlotFired = min(e.lastSlotFired, e.currentSlot)
+}
+
+func (e *Engine) BoundaryOffset(boundary Boundary) int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	period := e.tempo.SamplesPerBeat
+	switch boundary {
+	case BoundaryBeat:
+	case BoundaryBar:
+		period *= BeatsPerBar
+	default:
+		return 0
+	}
+
+	if period <= 0 {
+		return -1
+	}
+
+	next := (e.bufferStart + period - 1) / period * period
+	if next >= e.totalSamples {
+		return -1
+	}
+	return int(next - e.bufferStart)
+}
+
+func (e *Engine) slotBeatLocked(slot int64) float64 {
+	if e.tempo.SamplesPerBeat == 0 {
+		return 0
+	}
+	return float64(e.tempo.SlotStart(slot)) / float64(e.tempo.SamplesPerBeat)
+}
+
+func (e *Engine) resyncSlotLocked() {
+	e.currentSlot = e.tempo.SlotAt(e.totalSamples)
+	e.lastSlotFired = e.currentSlot
+}
+
+func (e *Engine) notifyStateChangeLocked() {
+	if e.onStateChange != nil {
+		e.onStateChange(e.tempo.BPM, e.tempo.Grid())
+	}
+}
+
 func (e *Engine) GetBeatPhase() float64 {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
@@ -162,7 +226,31 @@ func (e *Engine) GetCurrentSlot() int64 {
 func (e *Engine) GetSlotInBeat() int {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
-	return int(e.currentSlot % int64(e.tempo.Subdivision))
+	return e.tempo.SlotInCycle(e.currentSlot)
+}
+
+func (e *Engine) GetSlotPositions(fromBeat int64, beats int) []float64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if e.tempo.SamplesPerBeat == 0 || beats <= 0 {
+		return nil
+	}
+
+	from := fromBeat * e.tempo.SamplesPerBeat
+	to := from + int64(beats)*e.tempo.SamplesPerBeat
+
+	var positions []float64
+	for slot := e.tempo.SlotAt(from); ; slot++ {
+		start := e.tempo.SlotStart(slot)
+		if start >= to {
+			break
+		}
+		if start >= from {
+			positions = append(positions, float64(start-from)/float64(e.tempo.SamplesPerBeat))
+		}
+	}
+	return positions
 }
 
 func (e *Engine) QuantizedOnsets() <-chan QuantizedOnset {
@@ -174,8 +262,9 @@ func (e *Engine) RegisterTap(now time.Time) bool {
 	defer e.mu.Unlock()
 
 	updated := e.tempo.RegisterTap(now)
-	if updated && e.onStateChange != nil {
-		e.onStateChange(e.tempo.BPM, int(e.tempo.Subdivision))
+	if updated {
+		e.resyncSlotLocked()
+		e.notifyStateChangeLocked()
 	}
 	return updated
 }
@@ -185,9 +274,8 @@ func (e *Engine) SetBPM(bpm float64) {
 	defer e.mu.Unlock()
 
 	e.tempo.SetBPM(bpm)
-	if e.onStateChange != nil {
-		e.onStateChange(e.tempo.BPM, int(e.tempo.Subdivision))
-	}
+	e.resyncSlotLocked()
+	e.notifyStateChangeLocked()
 }
 
 func (e *Engine) AdjustBPM(delta float64) {
@@ -195,9 +283,8 @@ func (e *Engine) AdjustBPM(delta float64) {
 	defer e.mu.Unlock()
 
 	e.tempo.AdjustBPM(delta)
-	if e.onStateChange != nil {
-		e.onStateChange(e.tempo.BPM, int(e.tempo.Subdivision))
-	}
+	e.resyncSlotLocked()
+	e.notifyStateChangeLocked()
 }
 
 func (e *Engine) GetBPM() float64 {
@@ -211,9 +298,8 @@ func (e *Engine) SetSubdivision(sub Subdivision) {
 	defer e.mu.Unlock()
 
 	e.tempo.SetSubdivision(sub)
-	if e.onStateChange != nil {
-		e.onStateChange(e.tempo.BPM, int(e.tempo.Subdivision))
-	}
+	e.resyncSlotLocked()
+	e.notifyStateChangeLocked()
 }
 
 func (e *Engine) NextSubdivision() {
@@ -221,9 +307,8 @@ func (e *Engine) NextSubdivision() {
 	defer e.mu.Unlock()
 
 	e.tempo.NextSubdivision()
-	if e.onStateChange != nil {
-		e.onStateChange(e.tempo.BPM, int(e.tempo.Subdivision))
-	}
+	e.resyncSlotLocked()
+	e.notifyStateChangeLocked()
 }
 
 func (e *Engine) PrevSubdivision() {
@@ -231,9 +316,23 @@ func (e *Engine) PrevSubdivision() {
 	defer e.mu.Unlock()
 
 	e.tempo.PrevSubdivision()
-	if e.onStateChange != nil {
-		e.onStateChange(e.tempo.BPM, int(e.tempo.Subdivision))
-	}
+	e.resyncSlotLocked()
+	e.notifyStateChangeLocked()
+}
+
+func (e *Engine) AdjustSwing(delta int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.tempo.AdjustSwing(delta)
+	e.resyncSlotLocked()
+	e.notifyStateChangeLocked()
+}
+
+func (e *Engine) GetGrid() Grid {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.tempo.Grid()
 }
 
 func (e *Engine) GetSubdivision() Subdivision {
@@ -0,0 +1,60 @@
+package rhythm
+
+import "testing"
+
+func TestEngine(t *testing.T) {
+	t.Run("BoundaryOffset", func(t *testing.T) {
+		t.Run("should return offset of beat inside last buffer", func(t *testing.T) {
+			sut := NewEngine(EngineConfig{SampleRate: 48000, InitialBPM: 120, Subdivision: Sub4})
+			sut.ProcessBuffer(23990)
+			sut.ProcessBuffer(64)
+
+			got := sut.BoundaryOffset(BoundaryBeat)
+
+			if got != 10 {
+				t.Errorf("got %d, want 10", got)
+			}
+		})
+
+		t.Run("should return -1 when boundary is outside last buffer", func(t *testing.T) {
+			sut := NewEngine(EngineConfig{SampleRate: 48000, InitialBPM: 120, Subdivision: Sub4})
+			sut.ProcessBuffer(24010)
+			sut.ProcessBuffer(64)
+
+			got := sut.BoundaryOffset(BoundaryBar)
+
+			if got != -1 {
+				t.Errorf("got %d, want -1", got)
+			}
+		})
+
+		t.Run("should wait for bar start when switching on bar", func(t *testing.T) {
+			sut := NewEngine(EngineConfig{SampleRate: 48000, InitialBPM: 120, Subdivision: Sub4})
+			sut.ProcessBuffer(96000 - 32)
+			sut.ProcessBuffer(64)
+
+			got := sut.BoundaryOffset(BoundaryBar)
+
+			if got != 32 {
+				t.Errorf("got %d, want 32", got)
+			}
+		})
+	})
+
+	t.Run("SetSampleRate", func(t *testing.T) {
+		t.Run("should keep beat position when sample rate changes", func(t *testing.T) {
+			sut := NewEngine(EngineConfig{SampleRate: 48000, InitialBPM: 120, Subdivision: Sub4})
+			sut.ProcessBuffer(12000)
+
+			sut.SetSampleRate(96000)
+			sut.ProcessBuffer(23990)
+			sut.ProcessBuffer(64)
+
+			got := sut.BoundaryOffset(BoundaryBeat)
+
+			if got != 10 {
+				t.Errorf("got %d, want 10", got)
+			}
+		})
+	})
+}
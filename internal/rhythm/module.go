@@ -28,10 +28,14 @@ func NewEngineFromConfig(
 		SampleRate:  float32(audioCfg.SampleRate),
 		InitialBPM:  bpm,
 		Subdivision: sub,
+		Feel:        FeelFromString(stateCfg.RhythmFeel),
+		Swing:       stateCfg.RhythmSwing,
 		OnsetEvents: detector.Events(),
-		OnStateChange: func(newBPM float64, newSub int) {
+		OnStateChange: func(newBPM float64, grid Grid) {
 			stateCfg.SetRhythmBPM(newBPM)
-			stateCfg.SetRhythmSubdivision(newSub)
+			stateCfg.SetRhythmSubdivision(int(grid.Subdivision))
+			stateCfg.SetRhythmFeel(grid.Feel.String())
+			stateCfg.SetRhythmSwing(grid.Swing)
 		},
 	})
 }
@@ -15,30 +15,70 @@ const (
 	MaxBPM = 300.0
 
 	DefaultBPM = 120.0
+
+	BeatsPerBar = 4
 )
 
 type TempoState struct {
 	BPM         float64
 	Subdivision Subdivision
+	Feel        Feel
+	Swing       int
 	SampleRate  float32
 
 	SamplesPerBeat int64
 	SamplesPerSlot int64
 
+	BeatsPerCycle   int
+	SlotsPerCycle   int
+	SamplesPerCycle int64
+
+	slotStarts []int64
+
 	tapTimes []time.Time
 }
 
 func NewTempoState(bpm float64, subdivision Subdivision, sampleRate float32) *TempoState {
+	return NewTempoStateWithGrid(bpm, Grid{Subdivision: subdivision, Feel: FeelStraight, Swing: MinSwing}, sampleRate)
+}
+
+func NewTempoStateWithGrid(bpm float64, grid Grid, sampleRate float32) *TempoState {
 	t := &TempoState{
-		BPM:         clampBPM(bpm),
-		Subdivision: subdivision,
-		SampleRate:  sampleRate,
-		tapTimes:    make([]time.Time, 0, MaxTapHistory),
+		BPM:        clampBPM(bpm),
+		SampleRate: sampleRate,
+		tapTimes:   make([]time.Time, 0, MaxTapHistory),
 	}
+	t.applyGrid(grid)
 	t.recalculateDerivedValues()
 	return t
 }
 
+func (t *TempoState) Grid() Grid {
+	return Grid{Subdivision: t.Subdivision, Feel: t.Feel, Swing: t.Swing}
+}
+
+func (t *TempoState) SetGrid(grid Grid) {
+	t.applyGrid(grid)
+	t.recalculateDerivedValues()
+}
+
+func (t *TempoState) applyGrid(grid Grid) {
+	if grid.Subdivision.IsValid() {
+		t.Subdivision = grid.Subdivision
+	} else if !t.Subdivision.IsValid() {
+		t.Subdivision = Sub8
+	}
+	if grid.Feel.IsValid() {
+		t.Feel = grid.Feel
+	}
+	t.Swing = clampSwing(grid.Swing)
+}
+
+func (t *TempoState) SetSampleRate(sampleRate float32) {
+	t.SampleRate = sampleRate
+	t.recalculateDerivedValues()
+}
+
 func (t *TempoState) SetBPM(bpm float64) {
 	t.BPM = clampBPM(bpm)
 	t.recalculateDerivedValues()
@@ -50,17 +90,24 @@ func (t *TempoState) AdjustBPM(delta float64) {
 
 func (t *TempoState) SetSubdivision(sub Subdivision) {
 	if sub.IsValid() {
-		t.Subdivision = sub
-		t.recalculateDerivedValues()
+		grid := t.Grid()
+		grid.Subdivision = sub
+		t.SetGrid(grid)
 	}
 }
 
 func (t *TempoState) NextSubdivision() {
-	t.SetSubdivision(t.Subdivision.Next())
+	t.SetGrid(t.Grid().Next())
 }
 
 func (t *TempoState) PrevSubdivision() {
-	t.SetSubdivision(t.Subdivision.Prev())
+	t.SetGrid(t.Grid().Prev())
+}
+
+func (t *TempoState) AdjustSwing(delta int) {
+	grid := t.Grid()
+	grid.Swing += delta
+	t.SetGrid(grid)
 }
 
 func (t *TempoState) RegisterTap(now time.Time) bool {
@@ -110,15 +157,91 @@ func (t *TempoState) recalculateDerivedValues() {
 	samplesPerBeat := float64(t.SampleRate) * 60.0 / t.BPM
 	t.SamplesPerBeat = int64(samplesPerBeat)
 
-	if t.Subdivision > 0 {
-		t.SamplesPerSlot = t.SamplesPerBeat / int64(t.Subdivision)
-	} else {
-		t.SamplesPerSlot = t.SamplesPerBeat / int64(Sub8)
+	sub := int(t.Subdivision)
+	if sub <= 0 {
+		sub = int(Sub8)
+	}
+
+	switch t.Feel {
+	case FeelTriplet:
+		t.BeatsPerCycle = 2
+		t.SlotsPerCycle = 3 * sub
+	case FeelDotted:
+		t.BeatsPerCycle = 3
+		t.SlotsPerCycle = 2 * sub
+	default:
+		t.BeatsPerCycle = 1
+		t.SlotsPerCycle = sub
 	}
 
+	t.SamplesPerCycle = t.SamplesPerBeat * int64(t.BeatsPerCycle)
+	if t.SamplesPerCycle < int64(t.SlotsPerCycle) {
+		t.SamplesPerCycle = int64(t.SlotsPerCycle)
+	}
+
+	t.SamplesPerSlot = t.SamplesPerCycle / int64(t.SlotsPerCycle)
 	if t.SamplesPerSlot < 1 {
 		t.SamplesPerSlot = 1
 	}
+
+	t.recalculateSlotStarts()
+}
+
+func (t *TempoState) recalculateSlotStarts() {
+	if cap(t.slotStarts) < t.SlotsPerCycle {
+		t.slotStarts = make([]int64, t.SlotsPerCycle)
+	}
+	t.slotStarts = t.slotStarts[:t.SlotsPerCycle]
+
+	slotLen := float64(t.SamplesPerCycle) / float64(t.SlotsPerCycle)
+	swing := float64(t.Swing) / 100
+
+	for i := range t.slotStarts {
+		start := float64(i) * slotLen
+
+		if t.Feel == FeelStraight && i%2 == 1 {
+			start = float64(i-1)*slotLen + 2*slotLen*swing
+		}
+
+		t.slotStarts[i] = int64(math.Round(start))
+	}
+}
+
+func (t *TempoState) SlotAt(samplePos int64) int64 {
+	if samplePos < 0 || t.SamplesPerCycle <= 0 {
+		return 0
+	}
+
+	cycle := samplePos / t.SamplesPerCycle
+	offset := samplePos % t.SamplesPerCycle
+
+	lo, hi := 0, len(t.slotStarts)-1
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if t.slotStarts[mid] <= offset {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+
+	return cycle*int64(t.SlotsPerCycle) + int64(lo)
+}
+
+func (t *TempoState) SlotStart(slot int64) int64 {
+	if t.SlotsPerCycle <= 0 || slot < 0 {
+		return 0
+	}
+
+	cycle := slot / int64(t.SlotsPerCycle)
+	return cycle*t.SamplesPerCycle + t.slotStarts[slot%int64(t.SlotsPerCycle)]
+}
+
+func (t *TempoState) SlotInCycle(slot int64) int {
+	if t.SlotsPerCycle <= 0 {
+		return 0
+	}
+	return int(slot % int64(t.SlotsPerCycle))
 }
 
 func (t *TempoState) GetBeatDuration() time.Duration {
@@ -126,7 +249,7 @@ func (t *TempoState) GetBeatDuration() time.Duration {
 }
 
 func (t *TempoState) GetSlotDuration() time.Duration {
-	return t.GetBeatDuration() / time.Duration(t.Subdivision)
+	return t.GetBeatDuration() * time.Duration(t.BeatsPerCycle) / time.Duration(t.SlotsPerCycle)
 }
 
 func clampBPM(bpm float64) float64 {
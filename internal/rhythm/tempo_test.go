@@ -0,0 +1,98 @@
+package rhythm
+
+import "testing"
+
+func TestTempoState(t *testing.T) {
+	t.Run("SlotAt", func(t *testing.T) {
+		t.Run("should space straight slots evenly", func(t *testing.T) {
+			sut := NewTempoState(120, Sub4, 48000)
+
+			got := []int64{sut.SlotAt(0), sut.SlotAt(5999), sut.SlotAt(6000), sut.SlotAt(24000)}
+
+			want := []int64{0, 0, 1, 4}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("got %v, want %v", got, want)
+					break
+				}
+			}
+		})
+
+		t.Run("should delay off-beat slots by swing amount", func(t *testing.T) {
+			sut := NewTempoStateWithGrid(120, Grid{Subdivision: Sub2, Feel: FeelStraight, Swing: 66}, 48000)
+
+			got := sut.SlotStart(1)
+
+			want := int64(15840)
+			if got != want {
+				t.Errorf("got %d, want %d", got, want)
+			}
+			if sut.SlotAt(15839) != 0 || sut.SlotAt(15840) != 1 {
+				t.Errorf("slot boundary not at swung position")
+			}
+		})
+
+		t.Run("should place three triplet slots per beat for 1/2", func(t *testing.T) {
+			sut := NewTempoStateWithGrid(120, Grid{Subdivision: Sub2, Feel: FeelTriplet}, 48000)
+
+			got := sut.SlotAt(24000)
+
+			if got != 3 {
+				t.Errorf("got %d, want 3", got)
+			}
+			if sut.SlotStart(1) != 8000 {
+				t.Errorf("got SlotStart(1)=%d, want 8000", sut.SlotStart(1))
+			}
+		})
+
+		t.Run("should span dotted slots across beat boundaries", func(t *testing.T) {
+			sut := NewTempoStateWithGrid(120, Grid{Subdivision: Sub2, Feel: FeelDotted}, 48000)
+
+			got := sut.SlotStart(1)
+
+			want := int64(18000)
+			if got != want {
+				t.Errorf("got %d, want %d", got, want)
+			}
+			if sut.SlotAt(72000) != 4 {
+				t.Errorf("got SlotAt(72000)=%d, want 4", sut.SlotAt(72000))
+			}
+		})
+	})
+
+	t.Run("NextSubdivision", func(t *testing.T) {
+		t.Run("should cycle feels before moving to next subdivision", func(t *testing.T) {
+			sut := NewTempoState(120, Sub4, 48000)
+
+			var got []string
+			for i := 0; i < 4; i++ {
+				sut.NextSubdivision()
+				got = append(got, sut.Grid().String())
+			}
+
+			want := []string{"1/4T", "1/4.", "1/8", "1/8T"}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("got %v, want %v", got, want)
+					break
+				}
+			}
+		})
+	})
+
+	t.Run("AdjustSwing", func(t *testing.T) {
+		t.Run("should clamp swing to supported range", func(t *testing.T) {
+			sut := NewTempoState(120, Sub8, 48000)
+
+			sut.AdjustSwing(-SwingStep)
+			if sut.Swing != MinSwing {
+				t.Errorf("got Swing=%d, want %d", sut.Swing, MinSwing)
+			}
+
+			sut.AdjustSwing(100)
+			if sut.Swing != MaxSwing {
+				t.Errorf("got Swing=%d, want %d", sut.Swing, MaxSwing)
+			}
+		})
+	})
+}
@@ -76,9 +76,147 @@ func SubdivisionFromInt(val int) Subdivision {
 	return Sub8
 }
 
+type Feel int
+
+const (
+	FeelStraight Feel = iota
+	FeelTriplet
+	FeelDotted
+)
+
+func (f Feel) String() string {
+	switch f {
+	case FeelTriplet:
+		return "triplet"
+	case FeelDotted:
+		return "dotted"
+	default:
+		return "straight"
+	}
+}
+
+func (f Feel) Suffix() string {
+	switch f {
+	case FeelTriplet:
+		return "T"
+	case FeelDotted:
+		return "."
+	default:
+		return ""
+	}
+}
+
+func (f Feel) IsValid() bool {
+	switch f {
+	case FeelStraight, FeelTriplet, FeelDotted:
+		return true
+	default:
+		return false
+	}
+}
+
+func FeelFromString(val string) Feel {
+	switch val {
+	case "triplet":
+		return FeelTriplet
+	case "dotted":
+		return FeelDotted
+	default:
+		return FeelStraight
+	}
+}
+
+const (
+	MinSwing = 50
+
+	MaxSwing = 74
+
+	SwingStep = 4
+)
+
+func clampSwing(swing int) int {
+	return max(MinSwing, min(MaxSwing, swing))
+}
+
+type Grid struct {
+	Subdivision Subdivision
+	Feel        Feel
+	Swing       int
+}
+
+func (g Grid) String() string {
+	s := g.Subdivision.String() + g.Feel.Suffix()
+	if g.Feel == FeelStraight && g.Swing > MinSwing {
+		s += fmt.Sprintf(" sw%d%%", g.Swing)
+	}
+	return s
+}
+
+func (g Grid) Next() Grid {
+	if g.Feel < FeelDotted {
+		g.Feel++
+		return g
+	}
+	g.Feel = FeelStraight
+	g.Subdivision = g.Subdivision.Next()
+	return g
+}
+
+func (g Grid) Prev() Grid {
+	if g.Feel > FeelStraight {
+		g.Feel--
+		return g
+	}
+	g.Feel = FeelDotted
+	g.Subdivision = g.Subdivision.Prev()
+	return g
+}
+
+type Boundary int
+
+const (
+	BoundaryNone Boundary = iota
+	BoundaryBeat
+	BoundaryBar
+)
+
+func (b Boundary) String() string {
+	switch b {
+	case BoundaryBeat:
+		return "beat"
+	case BoundaryBar:
+		return "bar"
+	default:
+		return "immediate"
+	}
+}
+
+func (b Boundary) Next() Boundary {
+	switch b {
+	case BoundaryNone:
+		return BoundaryBeat
+	case BoundaryBeat:
+		return BoundaryBar
+	default:
+		return BoundaryNone
+	}
+}
+
+func BoundaryFromString(val string) Boundary {
+	switch val {
+	case "beat":
+		return BoundaryBeat
+	case "bar":
+		return BoundaryBar
+	default:
+		return BoundaryNone
+	}
+}
+
 type QuantizedOnset struct {
 	OriginalEvent onset.Event
 	BeatPosition  float64
 	SlotIndex     int
+	Beat          float64
 	WasQueued     bool
 }
@@ -0,0 +1,270 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chloyka/gorig/internal/audio"
+	configTypes "github.com/chloyka/gorig/internal/config/types"
+)
+
+type deviceColumn int
+
+const (
+	deviceColumnInput deviceColumn = iota
+	deviceColumnOutput
+)
+
+type devicePickerModel struct {
+	audioEngine *audio.Engine
+
+	inputs  []audio.DeviceDetails
+	outputs []audio.DeviceDetails
+
+	column         deviceColumn
+	inputCursor    int
+	outputCursor   int
+	selectedInput  int
+	selectedOutput int
+
+	lastError error
+}
+
+func newDevicePickerModel(engine *audio.Engine) devicePickerModel {
+	inputIndex, outputIndex := engine.CurrentDeviceIndexes()
+
+	return devicePickerModel{
+		audioEngine:    engine,
+		inputs:         engine.InputDevices(),
+		outputs:        engine.OutputDevices(),
+		inputCursor:    inputIndex,
+		outputCursor:   outputIndex,
+		selectedInput:  inputIndex,
+		selectedOutput: outputIndex,
+	}
+}
+
+func (m devicePickerModel) Update(msg tea.Msg) (devicePickerModel, tea.Cmd, Screen) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		key := msg.String()
+
+		count := len(m.inputs)
+		cursor := &m.inputCursor
+		if m.column == deviceColumnOutput {
+			count = len(m.outputs)
+			cursor = &m.outputCursor
+		}
+
+		switch {
+		case MatchKey(key, ActionUp):
+			if *cursor > 0 {
+				*cursor--
+			}
+		case MatchKey(key, ActionDown):
+			if *cursor < count-1 {
+				*cursor++
+			}
+		case MatchKey(key, ActionTab):
+			if m.column == deviceColumnInput {
+				m.column = deviceColumnOutput
+			} else {
+				m.column = deviceColumnInput
+			}
+		case MatchKey(key, ActionSpace):
+			if m.column == deviceColumnInput {
+				m.selectedInput = m.inputCursor
+			} else {
+				m.selectedOutput = m.outputCursor
+			}
+		case MatchKey(key, ActionEnter):
+			if m.column == deviceColumnInput {
+				m.selectedInput = m.inputCursor
+			} else {
+				m.selectedOutput = m.outputCursor
+			}
+			m.lastError = m.audioEngine.SelectDevices(m.selectedInput, m.selectedOutput)
+			if m.lastError == nil {
+				return m, nil, ScreenMain
+			}
+		case MatchKey(key, ActionChannel):
+			m.lastError = m.audioEngine.SetInputRouting(nextInputRouting(m.audioEngine.InputRouting(), m.currentInput().MaxInputChannels))
+		case MatchKey(key, ActionEsc):
+			return m, nil, ScreenMain
+		default:
+			if ch, err := strconv.Atoi(key); err == nil && ch >= 1 && ch <= m.currentOutput().MaxOutputChannels {
+				m.lastError = m.audioEngine.SetOutputRouting(toggleOutputChannel(m.audioEngine.OutputRouting(), ch-1))
+			}
+		}
+	}
+	return m, nil, ScreenDevicePicker
+}
+
+func (m devicePickerModel) currentInput() audio.DeviceDetails {
+	inputIndex, _ := m.audioEngine.CurrentDeviceIndexes()
+	if inputIndex < len(m.inputs) {
+		return m.inputs[inputIndex]
+	}
+	return audio.DeviceDetails{}
+}
+
+func (m devicePickerModel) currentOutput() audio.DeviceDetails {
+	_, outputIndex := m.audioEngine.CurrentDeviceIndexes()
+	if outputIndex < len(m.outputs) {
+		return m.outputs[outputIndex]
+	}
+	return audio.DeviceDetails{}
+}
+
+func nextInputRouting(current configTypes.InputRouting, maxChannels int) configTypes.InputRouting {
+	if maxChannels <= 1 {
+		return configTypes.InputRouting{}
+	}
+
+	if !current.Pair {
+		if current.Channel+1 < maxChannels {
+			return configTypes.InputRouting{Channel: current.Channel + 1}
+		}
+		return configTypes.InputRouting{Channel: 0, Pair: true}
+	}
+
+	if current.Channel+3 < maxChannels {
+		return configTypes.InputRouting{Channel: current.Channel + 2, Pair: true}
+	}
+	return configTypes.InputRouting{}
+}
+
+func toggleOutputChannel(current configTypes.OutputRouting, ch int) configTypes.OutputRouting {
+	channels := slices.Clone(current.Channels)
+
+	if i := slices.Index(channels, ch); i >= 0 {
+		if len(channels) == 1 {
+			return current
+		}
+		channels = slices.Delete(channels, i, i+1)
+	} else {
+		channels = append(channels, ch)
+		slices.Sort(channels)
+	}
+
+	return configTypes.OutputRouting{Channels: channels}
+}
+
+func formatInputRouting(r configTypes.InputRouting) string {
+	if r.Pair {
+		return fmt.Sprintf("ch %d+%d", r.Channel+1, r.Channel+2)
+	}
+	return fmt.Sprintf("ch %d", r.Channel+1)
+}
+
+func formatOutputRouting(r configTypes.OutputRouting) string {
+	parts := make([]string, 0, len(r.Channels))
+	for _, ch := range r.Channels {
+		parts = append(parts, strconv.Itoa(ch+1))
+	}
+	return "ch " + strings.Join(parts, ",")
+}
+
+func formatStreamFormat(f audio.StreamFormat) string {
+	if f.SampleRate == 0 {
+		return "(stream not running)"
+	}
+
+	s := fmt.Sprintf("%d Hz / %d frames", f.SampleRate, f.FramesPerBuffer)
+	if f.Fallback {
+		s += " (fallback)"
+	}
+	return s
+}
+
+func (m devicePickerModel) View() string {
+	var b strings.Builder
+
+	b.WriteString("\n Devices\n")
+	b.WriteString(" =======\n")
+
+	b.WriteString(renderDeviceList(" Input", m.inputs, m.inputCursor, m.selectedInput, m.column == deviceColumnInput))
+	b.WriteString(renderDeviceList(" Output", m.outputs, m.outputCursor, m.selectedOutput, m.column == deviceColumnOutput))
+
+	var details []audio.DeviceDetails
+	var cursor int
+	if m.column == deviceColumnInput {
+		details, cursor = m.inputs, m.inputCursor
+	} else {
+		details, cursor = m.outputs, m.outputCursor
+	}
+	if cursor >= 0 && cursor < len(details) {
+		b.WriteString(renderDeviceDetails(details[cursor]))
+	}
+
+	b.WriteString(fmt.Sprintf("\n Routing: IN %s -> OUT %s\n",
+		formatInputRouting(m.audioEngine.InputRouting()),
+		formatOutputRouting(m.audioEngine.OutputRouting()),
+	))
+	b.WriteString(fmt.Sprintf(" Format:  %s\n", formatStreamFormat(m.audioEngine.StreamFormat())))
+
+	if m.lastError != nil {
+		b.WriteString(fmt.Sprintf("\n Switch failed: %v\n", m.lastError))
+	}
+
+	b.WriteString("\n [j/k] Move  [tab] Input/Output  [space] Mark  [enter] Apply  [esc] Back\n")
+	b.WriteString(" [c] Cycle input channel  [1-9] Toggle output channel\n")
+
+	return b.String()
+}
+
+func renderDeviceList(title string, devices []audio.DeviceDetails, cursor, selected int, focused bool) string {
+	var b strings.Builder
+
+	if focused {
+		title += " <"
+	}
+	b.WriteString(fmt.Sprintf("\n%s\n", title))
+
+	if len(devices) == 0 {
+		b.WriteString("   (no devices)\n")
+		return b.String()
+	}
+
+	for i, d := range devices {
+		pointer := "  "
+		if focused && i == cursor {
+			pointer = "> "
+		}
+
+		mark := "  "
+		if i == selected {
+			mark = "* "
+		}
+
+		b.WriteString(fmt.Sprintf(" %s%s%s\n", pointer, mark, d.Name))
+	}
+
+	return b.String()
+}
+
+func renderDeviceDetails(d audio.DeviceDetails) string {
+	hostAPI := d.HostAPI
+	if hostAPI == "" {
+		hostAPI = "unknown"
+	}
+
+	return fmt.Sprintf(`
+ %s
+   Host API:     %s
+   Channels:     %d in / %d out
+   Sample rate:  %.0f Hz
+   In latency:   %s - %s
+   Out latency:  %s - %s
+`,
+		d.Name,
+		hostAPI,
+		d.MaxInputChannels, d.MaxOutputChannels,
+		d.DefaultSampleRate,
+		formatMillis(d.LowInputLatency), formatMillis(d.HighInputLatency),
+		formatMillis(d.LowOutputLatency), formatMillis(d.HighOutputLatency),
+	)
+}
@@ -31,6 +31,25 @@ const (
 	ActionBpmDown       = "bpmDown"
 	ActionSubdivisionUp = "subdivisionUp"
 	ActionSubdivisionDn = "subdivisionDn"
+	ActionSwingUp       = "swingUp"
+	ActionSwingDn       = "swingDn"
+
+	ActionPatternRecorder = "patternRecorder"
+	ActionSwitchMode      = "switchMode"
+	ActionStats           = "stats"
+	ActionDevices         = "devices"
+	ActionChannel         = "channel"
+	ActionStereoSplit     = "stereoSplit"
+	ActionInputGainUp     = "inputGainUp"
+	ActionInputGainDn     = "inputGainDn"
+	ActionVolumeUp        = "volumeUp"
+	ActionVolumeDn        = "volumeDn"
+	ActionSettings        = "settings"
+	ActionLatency         = "latency"
+	ActionLeft            = "left"
+	ActionRight           = "right"
+	ActionOversample      = "oversample"
+	ActionResponse        = "response"
 )
 
 var keyMap = map[string][]string{
@@ -63,8 +82,27 @@ var keyMap = map[string][]string{
 	ActionTapTempo:      {"t"},
 	ActionBpmUp:         {".", ">"},
 	ActionBpmDown:       {",", "<"},
-	ActionSubdivisionUp: {"]", "}"},
-	ActionSubdivisionDn: {"[", "{"},
+	ActionSubdivisionUp: {"]"},
+	ActionSubdivisionDn: {"["},
+	ActionSwingUp:       {"}"},
+	ActionSwingDn:       {"{"},
+
+	ActionPatternRecorder: {"m"},
+	ActionSwitchMode:      {"b"},
+	ActionStats:           {"l"},
+	ActionDevices:         {"v"},
+	ActionChannel:         {"c"},
+	ActionStereoSplit:     {"/"},
+	ActionInputGainUp:     {"G"},
+	ActionInputGainDn:     {"g"},
+	ActionVolumeUp:        {"=", "+"},
+	ActionVolumeDn:        {"-", "_"},
+	ActionSettings:        {"S"},
+	ActionLatency:         {"L"},
+	ActionLeft:            {"left", "h"},
+	ActionRight:           {"right", "l"},
+	ActionOversample:      {"o"},
+	ActionResponse:        {"f"},
 }
 
 func MatchKey(key, action string) bool {
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chloyka/gorig/internal/audio"
+)
+
+const latencyRuns = 5
+
+type LatencyResultMsg struct {
+	Report audio.LatencyReport
+	Err    error
+}
+
+type latencyModel struct {
+	audioEngine *audio.Engine
+	measuring   bool
+	report      *audio.LatencyReport
+	lastError   error
+}
+
+func newLatencyModel(engine *audio.Engine) latencyModel {
+	return latencyModel{audioEngine: engine}
+}
+
+func measureLatencyCmd(engine *audio.Engine) tea.Cmd {
+	return func() tea.Msg {
+		report, err := engine.MeasureLatency(latencyRuns)
+		return LatencyResultMsg{Report: report, Err: err}
+	}
+}
+
+func (m latencyModel) Update(msg tea.Msg) (latencyModel, tea.Cmd, Screen) {
+	switch msg := msg.(type) {
+	case LatencyResultMsg:
+		m.measuring = false
+		m.lastError = msg.Err
+		if msg.Err == nil {
+			m.report = &msg.Report
+		}
+	case tea.KeyMsg:
+		key := msg.String()
+
+		switch {
+		case MatchKey(key, ActionEnter):
+			if !m.measuring {
+				m.measuring = true
+				m.lastError = nil
+				return m, measureLatencyCmd(m.audioEngine), ScreenLatency
+			}
+		case MatchKey(key, ActionEsc):
+			if !m.measuring {
+				return m, nil, ScreenMain
+			}
+		}
+	}
+	return m, nil, ScreenLatency
+}
+
+func formatLatency(d time.Duration) string {
+	return fmt.Sprintf("%.2f ms", d.Seconds()*1000)
+}
+
+func (m latencyModel) View() string {
+	var b strings.Builder
+
+	b.WriteString("\n Round-Trip Latency\n")
+	b.WriteString(" ==================\n\n")
+	b.WriteString(" Connect an output to the input with a loopback cable.\n")
+	b.WriteString(" Turn monitoring down: a noise burst is played at -12 dBFS.\n\n")
+
+	switch {
+	case m.measuring:
+		b.WriteString(fmt.Sprintf(" Measuring (%d runs)...\n", latencyRuns))
+	case m.report != nil:
+		r := m.report
+		for i, run := range r.Runs {
+			b.WriteString(fmt.Sprintf("   Run %d: %6d samples  %s\n", i+1, run.Samples, formatLatency(run.Latency)))
+		}
+		b.WriteString(fmt.Sprintf("\n   Mean:     %s @ %d Hz\n", formatLatency(r.Mean), r.SampleRate))
+		b.WriteString(fmt.Sprintf("   Range:    %s - %s\n", formatLatency(r.Min), formatLatency(r.Max)))
+		b.WriteString(fmt.Sprintf("   Jitter:   %s\n", formatLatency(r.Jitter)))
+		b.WriteString(fmt.Sprintf("   Reported: %s (stream info)\n", formatLatency(r.Reported)))
+	}
+
+	if m.lastError != nil {
+		b.WriteString(fmt.Sprintf("\n Measurement failed: %v\n", m.lastError))
+	}
+
+	b.WriteString("\n [enter] Measure  [esc] Back\n")
+
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/chloyka/gorig/internal/audio"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const (
+	meterWidth = 30
+
+	meterFloorDb = -60.0
+
+	reductionRangeDb = 40.0
+)
+
+func renderLevelMeters(input, output audio.Levels, inputGainDb, outputVolumeDb float64) string {
+	return fmt.Sprintf("\n Levels:  (gain %+.1f dB, volume %+.1f dB)\n%s%s",
+		inputGainDb, outputVolumeDb,
+		renderLevelMeter("IN ", input),
+		renderLevelMeter("OUT", output),
+	)
+}
+
+func renderLevelMeter(label string, levels audio.Levels) string {
+	peakCells := dbToCells(levels.Peak)
+	rmsCells := dbToCells(levels.RMS)
+	holdCell := dbToCells(levels.Hold) - 1
+
+	var bar strings.Builder
+	for i := 0; i < meterWidth; i++ {
+		switch {
+		case i < rmsCells:
+			bar.WriteString("█")
+		case i < peakCells:
+			bar.WriteString("▒")
+		case i == holdCell:
+			bar.WriteString("│")
+		default:
+			bar.WriteString("░")
+		}
+	}
+
+	clip := "    "
+	if levels.Clip {
+		clip = "CLIP"
+	}
+
+	return fmt.Sprintf("   %s [%s] %s pk %s rms  %s\n",
+		label, bar.String(), formatDb(levels.Peak), formatDb(levels.RMS), clip)
+}
+
+func toDb(linear float32) float64 {
+	if linear <= 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(float64(linear))
+}
+
+func dbToCells(linear float32) int {
+	db := toDb(linear)
+	if db <= meterFloorDb {
+		return 0
+	}
+	cells := int(math.Round((db - meterFloorDb) / -meterFloorDb * meterWidth))
+	return min(cells, meterWidth)
+}
+
+func formatDb(linear float32) string {
+	db := toDb(linear)
+	if db <= meterFloorDb {
+		return "  -inf"
+	}
+	return fmt.Sprintf("%6.1f", db)
+}
+
+func renderGainReduction(meters []effects.EffectMeter) string {
+	if len(meters) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n Gain Reduction:\n")
+	for _, m := range meters {
+		cells := int(math.Round(max(0, min(m.GainReductionDb, reductionRangeDb)) / reductionRangeDb * meterWidth))
+		bar := strings.Repeat("█", cells) + strings.Repeat("░", meterWidth-cells)
+		b.WriteString(fmt.Sprintf("   %-16s [%s] %5.1f dB\n", m.Name, bar, max(m.GainReductionDb, 0)))
+	}
+	return b.String()
+}
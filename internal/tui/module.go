@@ -6,6 +6,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/chloyka/gorig/internal/audio"
 	"github.com/chloyka/gorig/internal/logger"
+	"github.com/chloyka/gorig/internal/pattern"
 	"github.com/chloyka/gorig/internal/pedal"
 	"github.com/chloyka/gorig/internal/preset"
 	"go.uber.org/fx"
@@ -16,9 +17,9 @@ type TUI struct {
 	logger  *logger.Logger
 }
 
-func NewTUI(pedalState *pedal.State, audioEngine *audio.Engine, presetManager *preset.Manager, log *logger.Logger) *TUI {
+func NewTUI(pedalState *pedal.State, audioEngine *audio.Engine, presetManager *preset.Manager, recorder *pattern.Recorder, log *logger.Logger) *TUI {
 	log.Debug("creating TUI")
-	m := NewModel(pedalState, audioEngine, presetManager, log)
+	m := NewModel(pedalState, audioEngine, presetManager, recorder, log)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	return &TUI{program: p, logger: log}
 }
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chloyka/gorig/internal/audio"
+)
+
+const deviceNoticeDuration = 5 * time.Second
+
+type DeviceNoticeMsg struct {
+	Notice audio.DeviceNotice
+}
+
+func listenForDeviceNotices(engine *audio.Engine) tea.Cmd {
+	return func() tea.Msg {
+		notice, ok := <-engine.DeviceNotices()
+		if !ok {
+			return nil
+		}
+		return DeviceNoticeMsg{Notice: notice}
+	}
+}
+
+func formatDeviceNotice(n audio.DeviceNotice) string {
+	switch n.Kind {
+	case audio.DeviceFallback:
+		return fmt.Sprintf("Device unavailable, switched to %s -> %s", n.Input, n.Output)
+	case audio.DeviceRestored:
+		return fmt.Sprintf("Device reconnected: %s -> %s", n.Input, n.Output)
+	default:
+		return fmt.Sprintf("Audio stream lost: %v (retrying)", n.Err)
+	}
+}
+
+func renderNotice(notice string) string {
+	if notice == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n ! %s\n", notice)
+}
@@ -0,0 +1,133 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chloyka/gorig/internal/pattern"
+	"github.com/chloyka/gorig/internal/rhythm"
+)
+
+type patternRecorderModel struct {
+	recorder   *pattern.Recorder
+	lastExport string
+	lastError  error
+}
+
+func newPatternRecorderModel(recorder *pattern.Recorder) patternRecorderModel {
+	return patternRecorderModel{
+		recorder: recorder,
+	}
+}
+
+func (m patternRecorderModel) Update(msg tea.Msg) (patternRecorderModel, tea.Cmd, Screen) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		key := msg.String()
+
+		switch {
+		case MatchKey(key, ActionEnter), MatchKey(key, ActionSpace):
+			switch m.recorder.State() {
+			case pattern.StateArmed, pattern.StateRecording:
+				m.recorder.Stop()
+			default:
+				m.recorder.Arm()
+				m.lastExport = ""
+				m.lastError = nil
+			}
+		case MatchKey(key, ActionUp):
+			m.recorder.SetBars(m.recorder.Bars() + 1)
+		case MatchKey(key, ActionDown):
+			m.recorder.SetBars(m.recorder.Bars() - 1)
+		case MatchKey(key, ActionSave):
+			m.lastExport, m.lastError = m.recorder.Export()
+		case MatchKey(key, ActionEsc):
+			return m, nil, ScreenMain
+		}
+	}
+	return m, nil, ScreenPatternRecorder
+}
+
+func (m patternRecorderModel) View() string {
+	var b strings.Builder
+
+	b.WriteString("\n Pattern Recorder\n")
+	b.WriteString(" ================\n\n")
+
+	state := m.recorder.State()
+	p := m.recorder.Pattern()
+
+	b.WriteString(fmt.Sprintf(" Bars: %d\n", m.recorder.Bars()))
+
+	switch state {
+	case pattern.StateArmed:
+		b.WriteString(fmt.Sprintf(" State: armed (starts in %d beats)\n\n", m.recorder.BeatsUntilStart()))
+	case pattern.StateRecording, pattern.StateDone:
+		b.WriteString(fmt.Sprintf(" State: %s  Grid: %s  Hits: %d\n\n", state, p.Grid.String(), len(p.Hits)))
+	default:
+		b.WriteString(" State: idle\n\n")
+	}
+
+	if p.Bars > 0 {
+		for bar := 0; bar < p.Bars; bar++ {
+			b.WriteString(fmt.Sprintf(" Bar %-2d %s\n", bar+1, renderPatternBar(p, bar)))
+		}
+	} else {
+		b.WriteString(" (no pattern - press [enter] to arm recording at the next bar)\n")
+	}
+
+	if m.lastError != nil {
+		b.WriteString(fmt.Sprintf("\n Export failed: %v\n", m.lastError))
+	} else if m.lastExport != "" {
+		b.WriteString(fmt.Sprintf("\n Exported: %s\n", m.lastExport))
+	}
+
+	b.WriteString("\n [enter] Arm/Stop  [j/k] Bars -/+  [s] Export MIDI  [esc] Back\n")
+
+	return b.String()
+}
+
+func renderPatternBar(p pattern.Pattern, bar int) string {
+	from := float64(bar * rhythm.BeatsPerBar)
+	to := from + rhythm.BeatsPerBar
+
+	var sb strings.Builder
+	for _, pos := range p.SlotPositions {
+		if pos < from || pos >= to {
+			continue
+		}
+
+		if math.Abs(pos-math.Round(pos)) < 1e-6 {
+			sb.WriteString("|")
+		}
+
+		sb.WriteString(velocityGlyph(findHit(p.Hits, pos)))
+	}
+	sb.WriteString("|")
+
+	return sb.String()
+}
+
+func findHit(hits []pattern.Hit, pos float64) *pattern.Hit {
+	for i := range hits {
+		if math.Abs(hits[i].Beat-pos) < 1e-3 {
+			return &hits[i]
+		}
+	}
+	return nil
+}
+
+func velocityGlyph(hit *pattern.Hit) string {
+	switch {
+	case hit == nil:
+		return "."
+	case hit.Velocity >= 96:
+		return "X"
+	case hit.Velocity >= 48:
+		return "x"
+	default:
+		return "o"
+	}
+}
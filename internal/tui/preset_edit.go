@@ -2,9 +2,14 @@ package tui
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	configTypes "github.com/chloyka/gorig/internal/config/types"
+	"github.com/chloyka/gorig/internal/effects"
+	"github.com/chloyka/gorig/internal/pedal"
 	"github.com/chloyka/gorig/internal/preset"
 )
 
@@ -13,6 +18,7 @@ type editMode int
 const (
 	editModeChain editMode = iota
 	editModeAdd
+	editModeParams
 )
 
 type presetEditModel struct {
@@ -22,15 +28,31 @@ type presetEditModel struct {
 	cursor           int
 	mode             editMode
 	addCursor        int
+	stereoSplit      *int
+	slots            []configTypes.SlotSettings
+	paramCursor      int
+	savedChain       []string
+	savedSlots       []configTypes.SlotSettings
+	irs              []string
 	presetManager    *preset.Manager
+	pedalState       *pedal.State
 }
 
-func newPresetEditModel(pm *preset.Manager, presetName string) presetEditModel {
+func newPresetEditModel(pm *preset.Manager, ps *pedal.State, presetName string) presetEditModel {
 	p := pm.GetPreset(presetName)
 	var chain []string
+	var stereoSplit *int
 	if p != nil {
-		chain = make([]string, len(p.EffectChain))
-		copy(chain, p.EffectChain)
+		chain = slices.Clone(p.EffectChain)
+		if p.StereoSplit != nil {
+			split := *p.StereoSplit
+			stereoSplit = &split
+		}
+	}
+
+	slots := make([]configTypes.SlotSettings, len(chain))
+	for i := range slots {
+		slots[i] = cloneSlot(p.Slot(i))
 	}
 
 	return presetEditModel{
@@ -39,8 +61,164 @@ func newPresetEditModel(pm *preset.Manager, presetName string) presetEditModel {
 		availableEffects: pm.GetAvailableEffects(),
 		cursor:           0,
 		mode:             editModeChain,
+		stereoSplit:      stereoSplit,
+		slots:            slots,
+		savedChain:       slices.Clone(chain),
+		savedSlots:       cloneSlots(slots),
+		irs:              ps.AvailableIRs(),
 		presetManager:    pm,
+		pedalState:       ps,
+	}
+}
+
+func cloneSlot(slot configTypes.SlotSettings) configTypes.SlotSettings {
+	slot.Params = maps.Clone(slot.Params)
+	return slot
+}
+
+func cloneSlots(slots []configTypes.SlotSettings) []configTypes.SlotSettings {
+	cloned := make([]configTypes.SlotSettings, len(slots))
+	for i, slot := range slots {
+		cloned[i] = cloneSlot(slot)
+	}
+	return cloned
+}
+
+func (m presetEditModel) cursorParams() []effects.Param {
+	if m.cursor < 0 || m.cursor >= len(m.chain) {
+		return nil
+	}
+	return effects.BuiltinParams(m.chain[m.cursor])
+}
+
+func (m presetEditModel) cursorHasResponse() bool {
+	return m.cursor >= 0 && m.cursor < len(m.chain) && effects.BuiltinHasResponse(m.chain[m.cursor])
+}
+
+func (m presetEditModel) cursorSlot() (string, map[string]float64) {
+	return m.chain[m.cursor], maps.Clone(m.slots[m.cursor].Params)
+}
+
+func (m presetEditModel) cursorUsesIR() bool {
+	return m.cursor >= 0 && m.cursor < len(m.chain) && effects.BuiltinUsesIR(m.chain[m.cursor])
+}
+
+func (m presetEditModel) paramRows() int {
+	rows := len(m.cursorParams())
+	if m.cursorUsesIR() {
+		rows++
+	}
+	return rows
+}
+
+func (m *presetEditModel) cycleIR(delta int) {
+	if len(m.irs) == 0 {
+		return
+	}
+
+	i := slices.Index(m.irs, m.slots[m.cursor].IR) + delta
+	switch {
+	case i < -1:
+		i = len(m.irs) - 1
+	case i >= len(m.irs):
+		i = -1
+	}
+
+	m.slots[m.cursor].IR = ""
+	if i >= 0 {
+		m.slots[m.cursor].IR = m.irs[i]
+	}
+}
+
+func (m *presetEditModel) cycleOversample() {
+	factors := effects.OversampleFactors
+	i := slices.Index(factors, max(1, m.slots[m.cursor].Oversample))
+	next := factors[(i+1)%len(factors)]
+
+	m.slots[m.cursor].Oversample = 0
+	if next > 1 {
+		m.slots[m.cursor].Oversample = next
+	}
+}
+
+func (m *presetEditModel) swapEffects(a, b int) {
+	m.chain[a], m.chain[b] = m.chain[b], m.chain[a]
+	m.slots[a], m.slots[b] = m.slots[b], m.slots[a]
+
+	if m.stereoSplit == nil {
+		return
+	}
+	switch *m.stereoSplit {
+	case a:
+		*m.stereoSplit = b
+	case b:
+		*m.stereoSplit = a
+	}
+}
+
+func (m *presetEditModel) deleteEffect(i int) {
+	m.chain = append(m.chain[:i], m.chain[i+1:]...)
+	m.slots = append(m.slots[:i], m.slots[i+1:]...)
+
+	if m.stereoSplit == nil {
+		return
+	}
+	if i < *m.stereoSplit {
+		*m.stereoSplit--
 	}
+	if *m.stereoSplit >= len(m.chain) {
+		m.stereoSplit = nil
+	}
+}
+
+func (m presetEditModel) paramValue(slot int, param effects.Param) float64 {
+	if v, ok := m.slots[slot].Params[param.Name]; ok {
+		return v
+	}
+	return param.Default
+}
+
+func (m presetEditModel) applyLive(slot int, name string, value float64) {
+	if slices.Equal(m.chain, m.savedChain) {
+		m.pedalState.SetEffectParam(m.presetName, slot, name, value)
+	}
+}
+
+func (m presetEditModel) restoreLive() {
+	for slot, name := range m.savedChain {
+		for _, param := range effects.BuiltinParams(name) {
+			value := param.Default
+			if v, ok := m.savedSlots[slot].Params[param.Name]; ok {
+				value = v
+			}
+			m.pedalState.SetEffectParam(m.presetName, slot, param.Name, value)
+		}
+	}
+}
+
+func (m *presetEditModel) adjustParam(steps float64) {
+	index := m.paramCursor
+	if m.cursorUsesIR() {
+		if index == 0 {
+			m.cycleIR(int(steps))
+			return
+		}
+		index--
+	}
+
+	params := m.cursorParams()
+	if index >= len(params) {
+		return
+	}
+
+	param := params[index]
+	value := param.Stepped(m.paramValue(m.cursor, param), steps)
+
+	if m.slots[m.cursor].Params == nil {
+		m.slots[m.cursor].Params = make(map[string]float64)
+	}
+	m.slots[m.cursor].Params[param.Name] = value
+	m.applyLive(m.cursor, param.Name, value)
 }
 
 func (m presetEditModel) Update(msg tea.Msg) (presetEditModel, tea.Cmd, Screen) {
@@ -60,17 +238,17 @@ func (m presetEditModel) Update(msg tea.Msg) (presetEditModel, tea.Cmd, Screen)
 				}
 			case MatchKey(key, "moveUp"):
 				if m.cursor > 0 && len(m.chain) > 0 {
-					m.chain[m.cursor], m.chain[m.cursor-1] = m.chain[m.cursor-1], m.chain[m.cursor]
+					m.swapEffects(m.cursor, m.cursor-1)
 					m.cursor--
 				}
 			case MatchKey(key, "moveDown"):
 				if m.cursor < len(m.chain)-1 {
-					m.chain[m.cursor], m.chain[m.cursor+1] = m.chain[m.cursor+1], m.chain[m.cursor]
+					m.swapEffects(m.cursor, m.cursor+1)
 					m.cursor++
 				}
 			case MatchKey(key, "delete"), MatchKey(key, "backspace"):
 				if len(m.chain) > 0 && m.cursor < len(m.chain) {
-					m.chain = append(m.chain[:m.cursor], m.chain[m.cursor+1:]...)
+					m.deleteEffect(m.cursor)
 					if m.cursor >= len(m.chain) && m.cursor > 0 {
 						m.cursor--
 					}
@@ -78,12 +256,53 @@ func (m presetEditModel) Update(msg tea.Msg) (presetEditModel, tea.Cmd, Screen)
 			case MatchKey(key, "add"):
 				m.mode = editModeAdd
 				m.addCursor = 0
+			case MatchKey(key, ActionEdit), MatchKey(key, ActionEnter):
+				if m.paramRows() > 0 {
+					m.mode = editModeParams
+					m.paramCursor = 0
+				}
+			case MatchKey(key, ActionOversample):
+				if m.cursor < len(m.chain) {
+					m.cycleOversample()
+				}
+			case MatchKey(key, ActionStereoSplit):
+				if m.stereoSplit != nil && *m.stereoSplit == m.cursor {
+					m.stereoSplit = nil
+				} else if len(m.chain) > 0 {
+					split := m.cursor
+					m.stereoSplit = &split
+				}
 			case MatchKey(key, "save"):
-				m.presetManager.UpdatePresetChain(m.presetName, m.chain)
+				if m.stereoSplit != nil && *m.stereoSplit >= len(m.chain) {
+					m.stereoSplit = nil
+				}
+				m.presetManager.UpdatePresetLayout(m.presetName, m.chain, m.stereoSplit, m.slots)
 				return m, nil, ScreenPresetList
 			case MatchKey(key, "esc"):
+				m.restoreLive()
 				return m, nil, ScreenPresetList
 			}
+		} else if m.mode == editModeParams {
+			switch {
+			case MatchKey(key, ActionUp):
+				if m.paramCursor > 0 {
+					m.paramCursor--
+				}
+			case MatchKey(key, ActionDown):
+				if m.paramCursor < m.paramRows()-1 {
+					m.paramCursor++
+				}
+			case MatchKey(key, ActionLeft):
+				m.adjustParam(-1)
+			case MatchKey(key, ActionRight):
+				m.adjustParam(1)
+			case MatchKey(key, ActionResponse):
+				if m.cursorHasResponse() {
+					return m, nil, ScreenResponse
+				}
+			case MatchKey(key, ActionEsc), MatchKey(key, ActionEnter):
+				m.mode = editModeChain
+			}
 		} else {
 			switch {
 			case MatchKey(key, "up"):
@@ -99,6 +318,7 @@ func (m presetEditModel) Update(msg tea.Msg) (presetEditModel, tea.Cmd, Screen)
 				if len(m.availableEffects) > 0 {
 					effectToAdd := m.availableEffects[m.addCursor]
 					m.chain = append(m.chain, effectToAdd)
+					m.slots = append(m.slots, configTypes.SlotSettings{})
 					m.mode = editModeChain
 					m.cursor = len(m.chain) - 1
 				}
@@ -122,14 +342,53 @@ func (m presetEditModel) View() string {
 			b.WriteString("   (empty chain - press [a] to add effects)\n")
 		} else {
 			for i, effect := range m.chain {
+				if m.stereoSplit != nil && *m.stereoSplit == i {
+					b.WriteString("   --- mono / stereo ---\n")
+				}
 				cursor := "  "
 				if i == m.cursor {
 					cursor = "> "
 				}
-				b.WriteString(fmt.Sprintf("%s%d. %s\n", cursor, i+1, effect))
+				oversample := ""
+				if factor := m.slots[i].Oversample; factor > 1 {
+					oversample = fmt.Sprintf("  (x%d oversampled)", factor)
+				}
+				b.WriteString(fmt.Sprintf("%s%d. %s%s\n", cursor, i+1, effect, oversample))
+			}
+		}
+		b.WriteString("\n [j/k] Navigate  [J/K] Reorder  [a] Add  [x] Delete  [e] Params  [o] Oversample  [/] Stereo split  [s] Save  [esc] Cancel\n")
+	} else if m.mode == editModeParams {
+		b.WriteString(fmt.Sprintf(" Parameters: %d. %s\n", m.cursor+1, m.chain[m.cursor]))
+		row := 0
+		if m.cursorUsesIR() {
+			cursor := "  "
+			if m.paramCursor == 0 {
+				cursor = "> "
+			}
+			ir := m.slots[m.cursor].IR
+			if ir == "" {
+				ir = "(none - bypass)"
 			}
+			b.WriteString(fmt.Sprintf("%s%-12s %s  (applies on save)\n", cursor, "ir", ir))
+			row++
+		}
+		for i, param := range m.cursorParams() {
+			cursor := "  "
+			if i+row == m.paramCursor {
+				cursor = "> "
+			}
+			value := m.paramValue(m.cursor, param)
+			if label, ok := param.Label(value); ok {
+				b.WriteString(fmt.Sprintf("%s%-12s %8s\n", cursor, param.Name, label))
+				continue
+			}
+			b.WriteString(fmt.Sprintf("%s%-12s %8.1f %s\n", cursor, param.Name, value, param.Unit))
+		}
+		if m.cursorHasResponse() {
+			b.WriteString("\n [j/k] Navigate  [h/l] Adjust  [f] Response  [esc] Back\n")
+		} else {
+			b.WriteString("\n [j/k] Navigate  [h/l] Adjust  [esc] Back\n")
 		}
-		b.WriteString("\n [j/k] Navigate  [J/K] Reorder  [a] Add  [x] Delete  [s] Save  [esc] Cancel\n")
 	} else {
 		b.WriteString(" Select effect to add:\n")
 		if len(m.availableEffects) == 0 {
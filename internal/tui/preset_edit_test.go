@@ -0,0 +1,152 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	configTypes "github.com/chloyka/gorig/internal/config/types"
+	"github.com/chloyka/gorig/internal/effects"
+	_ "github.com/chloyka/gorig/internal/effects/builtin"
+	"github.com/chloyka/gorig/internal/logger"
+	"github.com/chloyka/gorig/internal/pedal"
+	"github.com/chloyka/gorig/internal/preset"
+	"go.uber.org/zap"
+)
+
+func newTestEditModel(split int, chain ...string) presetEditModel {
+	return presetEditModel{
+		chain:       chain,
+		slots:       make([]configTypes.SlotSettings, len(chain)),
+		stereoSplit: &split,
+	}
+}
+
+func newTestPresetEditor(t *testing.T, p configTypes.Preset, saveChan chan struct{}, onPresetChanged func([]string)) presetEditModel {
+	t.Helper()
+
+	log := &logger.Logger{Logger: zap.NewNop()}
+	presets := &configTypes.PresetsConfig{Presets: []configTypes.Preset{p}, ActivePreset: p.Name}
+	presets.SetSaveChan(saveChan)
+	state := &configTypes.StateConfig{EffectsEnabled: true}
+	chain := effects.NewChain(log, t.TempDir(), t.TempDir(), state, presets, effects.TransitionConfig{SampleRate: 48000, MaxFrames: 256})
+	manager := preset.NewManager(log, presets, chain.GetAvailableEffectNames, onPresetChanged, nil)
+
+	return newPresetEditModel(manager, pedal.New(log, chain, manager), p.Name)
+}
+
+func pressKeys(m presetEditModel, keys ...string) presetEditModel {
+	for _, key := range keys {
+		m, _, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+	return m
+}
+
+func TestPresetEditModel(t *testing.T) {
+	t.Run("Update", func(t *testing.T) {
+		t.Run("should restore saved params when editing is cancelled", func(t *testing.T) {
+			sut := newTestPresetEditor(t, configTypes.Preset{
+				Name:        "clean",
+				EffectChain: []string{"noise gate"},
+				Slots:       []configTypes.SlotSettings{{Params: map[string]float64{"threshold": -40}}},
+			}, nil, nil)
+
+			sut = pressKeys(sut, "e", "l", "l")
+			edited := sut.slots[0].Params["threshold"]
+			sut, _, screen := sut.Update(tea.KeyMsg{Type: tea.KeyEsc})
+			sut, _, screen = sut.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+			if edited != -38 {
+				t.Fatalf("got edited threshold %v, want -38", edited)
+			}
+			if screen != ScreenPresetList {
+				t.Errorf("got screen %v, want preset list", screen)
+			}
+			if got := sut.savedSlots[0].Params["threshold"]; got != -40 {
+				t.Errorf("got restored threshold %v, want -40", got)
+			}
+		})
+
+		t.Run("should save the edited layout with one write and one rebuild", func(t *testing.T) {
+			saveChan := make(chan struct{}, 4)
+			rebuilds := 0
+			sut := newTestPresetEditor(t, configTypes.Preset{
+				Name:        "clean",
+				EffectChain: []string{"noise gate", "compressor"},
+			}, saveChan, func([]string) { rebuilds++ })
+
+			sut = pressKeys(sut, "/", "e", "l", "l")
+			sut, _, _ = sut.Update(tea.KeyMsg{Type: tea.KeyEsc})
+			sut = pressKeys(sut, "s")
+
+			if len(saveChan) != 1 {
+				t.Errorf("got %d config writes, want 1", len(saveChan))
+			}
+			if rebuilds != 1 {
+				t.Errorf("got %d chain rebuilds, want 1", rebuilds)
+			}
+		})
+	})
+
+	t.Run("swapEffects", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			split     int
+			a, b      int
+			wantSplit int
+		}{
+			{name: "should follow the first stereo effect moving up", split: 2, a: 2, b: 1, wantSplit: 1},
+			{name: "should follow the first stereo effect moving down", split: 1, a: 1, b: 2, wantSplit: 2},
+			{name: "should follow the first stereo effect regardless of swap order", split: 1, a: 0, b: 1, wantSplit: 0},
+			{name: "should keep split when effects swap on one side", split: 2, a: 0, b: 1, wantSplit: 2},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := newTestEditModel(tt.split, "drive", "chorus", "delay")
+				want := sut.chain[tt.split]
+
+				sut.swapEffects(tt.a, tt.b)
+
+				if got := *sut.stereoSplit; got != tt.wantSplit {
+					t.Errorf("got split %d, want %d", got, tt.wantSplit)
+				}
+				if got := sut.chain[*sut.stereoSplit]; got != want {
+					t.Errorf("got %q after split, want %q", got, want)
+				}
+			})
+		}
+	})
+
+	t.Run("deleteEffect", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			split     int
+			delete    int
+			wantSplit int
+			wantNil   bool
+		}{
+			{name: "should shift split when a mono effect is deleted", split: 2, delete: 0, wantSplit: 1},
+			{name: "should keep split when a stereo effect after it is deleted", split: 1, delete: 2, wantSplit: 1},
+			{name: "should move split to the next effect when its effect is deleted", split: 1, delete: 1, wantSplit: 1},
+			{name: "should clear split when no effect is left after it", split: 2, delete: 2, wantNil: true},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				sut := newTestEditModel(tt.split, "drive", "chorus", "delay")
+
+				sut.deleteEffect(tt.delete)
+
+				if tt.wantNil {
+					if sut.stereoSplit != nil {
+						t.Errorf("got split %d, want nil", *sut.stereoSplit)
+					}
+					return
+				}
+				if sut.stereoSplit == nil || *sut.stereoSplit != tt.wantSplit {
+					t.Errorf("got split %v, want %d", sut.stereoSplit, tt.wantSplit)
+				}
+			})
+		}
+	})
+}
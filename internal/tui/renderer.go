@@ -9,6 +9,7 @@ import (
 	"github.com/chloyka/gorig/internal/audio"
 	"github.com/chloyka/gorig/internal/logger"
 	"github.com/chloyka/gorig/internal/logger/keys"
+	"github.com/chloyka/gorig/internal/pattern"
 	"github.com/chloyka/gorig/internal/pedal"
 	"github.com/chloyka/gorig/internal/preset"
 	"github.com/chloyka/gorig/internal/rhythm"
@@ -18,8 +19,13 @@ const hotkeysHelp = `
  Hotkeys:
    [d] Toggle Effects   [t] Tap Tempo
    [r] Reload Effects   [,/.] BPM -/+
-   [p] Presets Menu     [/]] Subdivision
-   [i/o] Input/Output   [q] Quit
+   [p] Presets Menu     [/]] Grid
+   [i/o] Input/Output   [{/}] Swing
+   [v] Device Picker    [l] Performance
+   [m] Pattern Recorder [b] Preset Switch Sync
+   [g/G] Input Gain     [-/=] Volume
+   [S] Audio Settings   [L] Measure Latency
+   [q] Quit
 `
 
 type LampDecayMsg struct{}
@@ -38,11 +44,17 @@ type model struct {
 	pedalState    *pedal.State
 	audioEngine   *audio.Engine
 	presetManager *preset.Manager
+	recorder      *pattern.Recorder
 	logger        *logger.Logger
 	quitting      bool
 
 	lampOn          bool
 	lastOnsetEnergy float32
+	showStats       bool
+	inputLevels     audio.Levels
+	outputLevels    audio.Levels
+	notice          string
+	noticeUntil     time.Time
 
 	rhythmViz rhythmVisualizer
 
@@ -50,21 +62,27 @@ type model struct {
 	presetList    presetListModel
 	presetCreate  presetCreateModel
 	presetEdit    presetEditModel
+	patternRec    patternRecorderModel
+	devicePicker  devicePickerModel
+	settings      settingsModel
+	latency       latencyModel
+	response      responseModel
 }
 
-func NewModel(pedalState *pedal.State, audioEngine *audio.Engine, presetManager *preset.Manager, logger *logger.Logger) model {
+func NewModel(pedalState *pedal.State, audioEngine *audio.Engine, presetManager *preset.Manager, recorder *pattern.Recorder, logger *logger.Logger) model {
 	logger.Debug("tui model created")
 
 	rhythmViz := newRhythmVisualizer()
 	if re := audioEngine.RhythmEngine(); re != nil {
 		rhythmViz.bpm = re.GetBPM()
-		rhythmViz.subdivision = re.GetSubdivision()
+		rhythmViz.grid = re.GetGrid()
 	}
 
 	return model{
 		pedalState:    pedalState,
 		audioEngine:   audioEngine,
 		presetManager: presetManager,
+		recorder:      recorder,
 		logger:        logger,
 		currentScreen: ScreenMain,
 		rhythmViz:     rhythmViz,
@@ -99,7 +117,7 @@ func (m model) Init() tea.Cmd {
 		cmds = append(cmds, listenForQuantizedOnsets(re))
 	}
 
-	cmds = append(cmds, rhythmTickCmd())
+	cmds = append(cmds, rhythmTickCmd(), listenForDeviceNotices(m.audioEngine))
 
 	return tea.Batch(cmds...)
 }
@@ -108,13 +126,21 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case RhythmTickMsg:
+		m.inputLevels = m.audioEngine.InputLevels()
+		m.outputLevels = m.audioEngine.OutputLevels()
+
+		if m.notice != "" && time.Now().After(m.noticeUntil) {
+			m.notice = ""
+		}
 
 		if re := m.audioEngine.RhythmEngine(); re != nil {
+			beatCount := re.GetBeatCount()
 			m.rhythmViz.Update(
 				re.GetBPM(),
-				re.GetSubdivision(),
+				re.GetGrid(),
 				re.GetBeatPhase(),
-				re.GetBeatCount(),
+				beatCount,
+				re.GetSlotPositions(beatCount-beatCount%beatsToShow, beatsToShow),
 			)
 		}
 		return m, rhythmTickCmd()
@@ -124,8 +150,9 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.lampOn = true
 		m.lastOnsetEnergy = msg.Onset.OriginalEvent.Energy
 
-		beatInDisplay := int(m.rhythmViz.beatCount) % beatsToShow
-		m.rhythmViz.AddHitMarker(beatInDisplay, msg.Onset.SlotIndex)
+		m.recorder.Record(msg.Onset)
+
+		m.rhythmViz.AddHitMarker(msg.Onset.Beat)
 
 		var cmds []tea.Cmd
 		cmds = append(cmds, tea.Tick(lampDecayDuration, func(time.Time) tea.Msg {
@@ -139,6 +166,11 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case LampDecayMsg:
 		m.lampOn = false
 		return m, nil
+
+	case DeviceNoticeMsg:
+		m.notice = formatDeviceNotice(msg.Notice)
+		m.noticeUntil = time.Now().Add(deviceNoticeDuration)
+		return m, listenForDeviceNotices(m.audioEngine)
 	}
 
 	switch m.currentScreen {
@@ -152,7 +184,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if nextScreen == ScreenPresetCreate {
 				m.presetCreate = newPresetCreateModel(m.presetManager)
 			} else if nextScreen == ScreenPresetEdit && editPresetName != "" {
-				m.presetEdit = newPresetEditModel(m.presetManager, editPresetName)
+				m.presetEdit = newPresetEditModel(m.presetManager, m.pedalState, editPresetName)
 			}
 		}
 		return m, cmd
@@ -177,9 +209,57 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.currentScreen = nextScreen
 			if nextScreen == ScreenPresetList {
 				m.presetList = newPresetListModel(m.presetManager)
+			} else if nextScreen == ScreenResponse {
+				effect, values := m.presetEdit.cursorSlot()
+				m.response = newResponseModel(effect, values, m.audioEngine.StreamFormat().SampleRate)
 			}
 		}
 		return m, cmd
+
+	case ScreenPatternRecorder:
+		var cmd tea.Cmd
+		var nextScreen Screen
+		m.patternRec, cmd, nextScreen = m.patternRec.Update(msg)
+		if nextScreen != ScreenPatternRecorder {
+			m.currentScreen = nextScreen
+		}
+		return m, cmd
+
+	case ScreenDevicePicker:
+		var cmd tea.Cmd
+		var nextScreen Screen
+		m.devicePicker, cmd, nextScreen = m.devicePicker.Update(msg)
+		if nextScreen != ScreenDevicePicker {
+			m.currentScreen = nextScreen
+		}
+		return m, cmd
+
+	case ScreenSettings:
+		var cmd tea.Cmd
+		var nextScreen Screen
+		m.settings, cmd, nextScreen = m.settings.Update(msg)
+		if nextScreen != ScreenSettings {
+			m.currentScreen = nextScreen
+		}
+		return m, cmd
+
+	case ScreenLatency:
+		var cmd tea.Cmd
+		var nextScreen Screen
+		m.latency, cmd, nextScreen = m.latency.Update(msg)
+		if nextScreen != ScreenLatency {
+			m.currentScreen = nextScreen
+		}
+		return m, cmd
+
+	case ScreenResponse:
+		var cmd tea.Cmd
+		var nextScreen Screen
+		m.response, cmd, nextScreen = m.response.Update(msg)
+		if nextScreen != ScreenResponse {
+			m.currentScreen = nextScreen
+		}
+		return m, cmd
 	}
 
 	switch msg := msg.(type) {
@@ -210,6 +290,45 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.presetList = newPresetListModel(m.presetManager)
 			m.currentScreen = ScreenPresetList
 			return m, nil
+		case MatchKey(key, ActionPatternRecorder):
+			m.logger.Debug("pattern recorder requested")
+			m.patternRec = newPatternRecorderModel(m.recorder)
+			m.currentScreen = ScreenPatternRecorder
+			return m, nil
+		case MatchKey(key, ActionSwitchMode):
+			mode := m.presetManager.CycleSwitchMode()
+			m.logger.Debug("preset switch mode cycled", keys.RhythmBoundary(mode.String()))
+			return m, nil
+		case MatchKey(key, ActionStats):
+			m.showStats = !m.showStats
+			return m, nil
+		case MatchKey(key, ActionInputGainUp):
+			m.audioEngine.AdjustInputGain(audio.GainStepDb)
+			return m, nil
+		case MatchKey(key, ActionInputGainDn):
+			m.audioEngine.AdjustInputGain(-audio.GainStepDb)
+			return m, nil
+		case MatchKey(key, ActionVolumeUp):
+			m.audioEngine.AdjustOutputVolume(audio.GainStepDb)
+			return m, nil
+		case MatchKey(key, ActionVolumeDn):
+			m.audioEngine.AdjustOutputVolume(-audio.GainStepDb)
+			return m, nil
+		case MatchKey(key, ActionDevices):
+			m.logger.Debug("device picker requested")
+			m.devicePicker = newDevicePickerModel(m.audioEngine)
+			m.currentScreen = ScreenDevicePicker
+			return m, nil
+		case MatchKey(key, ActionSettings):
+			m.logger.Debug("audio settings requested")
+			m.settings = newSettingsModel(m.audioEngine)
+			m.currentScreen = ScreenSettings
+			return m, nil
+		case MatchKey(key, ActionLatency):
+			m.logger.Debug("latency measurement requested")
+			m.latency = newLatencyModel(m.audioEngine)
+			m.currentScreen = ScreenLatency
+			return m, nil
 		case MatchKey(key, ActionInput):
 			m.logger.Debug("next input device requested")
 			m.audioEngine.NextInputDevice()
@@ -240,13 +359,25 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case MatchKey(key, ActionSubdivisionUp):
 			if re := m.audioEngine.RhythmEngine(); re != nil {
 				re.NextSubdivision()
-				m.rhythmViz.subdivision = re.GetSubdivision()
+				m.rhythmViz.grid = re.GetGrid()
 			}
 			return m, nil
 		case MatchKey(key, ActionSubdivisionDn):
 			if re := m.audioEngine.RhythmEngine(); re != nil {
 				re.PrevSubdivision()
-				m.rhythmViz.subdivision = re.GetSubdivision()
+				m.rhythmViz.grid = re.GetGrid()
+			}
+			return m, nil
+		case MatchKey(key, ActionSwingUp):
+			if re := m.audioEngine.RhythmEngine(); re != nil {
+				re.AdjustSwing(rhythm.SwingStep)
+				m.rhythmViz.grid = re.GetGrid()
+			}
+			return m, nil
+		case MatchKey(key, ActionSwingDn):
+			if re := m.audioEngine.RhythmEngine(); re != nil {
+				re.AdjustSwing(-rhythm.SwingStep)
+				m.rhythmViz.grid = re.GetGrid()
 			}
 			return m, nil
 		}
@@ -269,6 +400,16 @@ func (m model) View() string {
 		return m.presetCreate.View()
 	case ScreenPresetEdit:
 		return m.presetEdit.View()
+	case ScreenPatternRecorder:
+		return m.patternRec.View()
+	case ScreenDevicePicker:
+		return m.devicePicker.View()
+	case ScreenSettings:
+		return m.settings.View()
+	case ScreenLatency:
+		return m.latency.View()
+	case ScreenResponse:
+		return m.response.View()
 	}
 
 	amp := getAmpArt(m.pedalState.IsEffectsOn(), m.lampOn)
@@ -281,20 +422,36 @@ func (m model) View() string {
 		if status != nil && status.MissingCount > 0 {
 			presetInfo += fmt.Sprintf(" (%d effects missing!)", status.MissingCount)
 		}
+		if pending := m.pedalState.PendingSwitch(); pending != rhythm.BoundaryNone {
+			presetInfo += fmt.Sprintf(" [armed: switching on next %s]", pending)
+		}
 		presetInfo += "\n"
 	} else {
 		presetInfo = "\n Preset: (none - press [p] to create)\n"
 	}
+	presetInfo += fmt.Sprintf(" Switch: %s\n", m.presetManager.GetSwitchMode())
 
 	effects := m.pedalState.GetEffects()
-	var chainParts []string
-	for _, e := range effects {
-		chainParts = append(chainParts, fmt.Sprintf("[%s]", e.Name))
+	var chainParts, failures []string
+	for i, e := range effects {
+		if e.Stereo && (i == 0 || !effects[i-1].Stereo) {
+			chainParts = append(chainParts, "<stereo>")
+		}
+		switch {
+		case e.Err != nil:
+			chainParts = append(chainParts, fmt.Sprintf("[%s !]", e.Name))
+			failures = append(failures, fmt.Sprintf("   ! %s: %v\n", e.Name, e.Err))
+		case e.Oversample > 1:
+			chainParts = append(chainParts, fmt.Sprintf("[%s x%d]", e.Name, e.Oversample))
+		default:
+			chainParts = append(chainParts, fmt.Sprintf("[%s]", e.Name))
+		}
 	}
 
 	chainDisplay := ""
 	if len(effects) > 0 {
 		chainDisplay = fmt.Sprintf("\n Effects Chain:\n   IN -> %s -> OUT\n", strings.Join(chainParts, " -> "))
+		chainDisplay += strings.Join(failures, "")
 	} else {
 		chainDisplay = "\n Effects Chain: (empty - add effects in preset menu)\n"
 	}
@@ -303,9 +460,18 @@ func (m model) View() string {
  Devices:
    IN:  %s
    OUT: %s
-`, m.audioEngine.CurrentInputDevice(), m.audioEngine.CurrentOutputDevice())
+   FMT: %s
+`, m.audioEngine.CurrentInputDevice(), m.audioEngine.CurrentOutputDevice(), formatStreamFormat(m.audioEngine.StreamFormat()))
 
 	rhythmDisplay := "\n" + m.rhythmViz.View()
 
-	return fmt.Sprintf("%s%s%s%s%s%s", amp, presetInfo, chainDisplay, devices, rhythmDisplay, hotkeysHelp)
+	levelsDisplay := renderLevelMeters(m.inputLevels, m.outputLevels, m.audioEngine.InputGainDb(), m.audioEngine.OutputVolumeDb()) +
+		renderGainReduction(m.pedalState.EffectMeters())
+
+	statsDisplay := ""
+	if m.showStats {
+		statsDisplay = renderStatsPanel(m.audioEngine.Stats())
+	}
+
+	return fmt.Sprintf("%s%s%s%s%s%s%s%s%s", amp, renderNotice(m.notice), presetInfo, chainDisplay, devices, levelsDisplay, statsDisplay, rhythmDisplay, hotkeysHelp)
 }
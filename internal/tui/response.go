@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chloyka/gorig/internal/effects"
+)
+
+const (
+	responseWidth  = 61
+	responseHeight = 17
+
+	responseMinHz = 20
+	responseMaxHz = 20000
+
+	responseMinSpanDb = 12
+	responseMaxSpanDb = 24
+
+	responseFallbackRate = 48000
+)
+
+var responseFreqLabels = []struct {
+	hz    float64
+	label string
+}{
+	{100, "100"},
+	{1000, "1k"},
+	{10000, "10k"},
+}
+
+type responseModel struct {
+	effect     string
+	values     map[string]float64
+	sampleRate int
+}
+
+func newResponseModel(effect string, values map[string]float64, sampleRate int) responseModel {
+	if sampleRate <= 0 {
+		sampleRate = responseFallbackRate
+	}
+	return responseModel{effect: effect, values: values, sampleRate: sampleRate}
+}
+
+func (m responseModel) Update(msg tea.Msg) (responseModel, tea.Cmd, Screen) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		key := msg.String()
+		if MatchKey(key, ActionEsc) || MatchKey(key, ActionEnter) || MatchKey(key, ActionResponse) {
+			return m, nil, ScreenPresetEdit
+		}
+	}
+	return m, nil, ScreenResponse
+}
+
+func responseColumn(hz float64) int {
+	pos := math.Log(hz/responseMinHz) / math.Log(responseMaxHz/responseMinHz)
+	return int(math.Round(pos * (responseWidth - 1)))
+}
+
+func responseSpan(response []float64) float64 {
+	span := float64(responseMinSpanDb)
+	for _, db := range response {
+		span = max(span, math.Ceil(math.Abs(db)/6)*6)
+	}
+	return min(span, responseMaxSpanDb)
+}
+
+func (m responseModel) View() string {
+	freqs := make([]float64, responseWidth)
+	for i := range freqs {
+		freqs[i] = responseMinHz * math.Pow(responseMaxHz/responseMinHz, float64(i)/(responseWidth-1))
+	}
+	response := effects.BuiltinResponse(m.effect, m.values, m.sampleRate, freqs)
+
+	span := responseSpan(response)
+
+	row := func(db float64) int {
+		db = max(-span, min(db, span))
+		return int(math.Round((span - db) / (2 * span) * (responseHeight - 1)))
+	}
+
+	grid := make([][]rune, responseHeight)
+	for r := range grid {
+		grid[r] = []rune(strings.Repeat(" ", responseWidth))
+	}
+	zero := row(0)
+	for c := range grid[zero] {
+		grid[zero][c] = '·'
+	}
+
+	prev := -1
+	for c, db := range response {
+		cur := row(db)
+		if prev >= 0 {
+			for r := min(prev, cur) + 1; r < max(prev, cur); r++ {
+				grid[r][c] = '│'
+			}
+		}
+		grid[cur][c] = '●'
+		prev = cur
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("\n Frequency Response: %s\n", m.effect))
+	b.WriteString(" ====================\n\n")
+
+	for r, line := range grid {
+		label := "        "
+		switch r {
+		case 0:
+			label = fmt.Sprintf("%+5.0f dB", span)
+		case zero:
+			label = "    0 dB"
+		case responseHeight - 1:
+			label = fmt.Sprintf("%+5.0f dB", -span)
+		}
+		b.WriteString(fmt.Sprintf(" %s │%s\n", label, string(line)))
+	}
+
+	axis := []rune(strings.Repeat(" ", responseWidth+4))
+	for _, f := range responseFreqLabels {
+		col := responseColumn(f.hz)
+		copy(axis[col:], []rune(f.label))
+	}
+	b.WriteString(fmt.Sprintf("          └%s\n", strings.Repeat("─", responseWidth)))
+	b.WriteString(fmt.Sprintf("           %s\n", strings.TrimRight(string(axis), " ")))
+	b.WriteString(fmt.Sprintf("\n Sample rate: %d Hz  (20 Hz - 20 kHz)\n", m.sampleRate))
+
+	b.WriteString("\n [esc] Back\n")
+
+	return b.String()
+}
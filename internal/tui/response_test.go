@@ -0,0 +1,31 @@
+package tui
+
+import (
+	"math"
+	"testing"
+)
+
+func TestResponse(t *testing.T) {
+	t.Run("responseSpan", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			response []float64
+			want     float64
+		}{
+			{name: "should keep the minimum span for a flat response", response: []float64{0, 1, -2}, want: responseMinSpanDb},
+			{name: "should round the span up to the next 6 dB", response: []float64{-3, 13}, want: 18},
+			{name: "should cap the span for a deep notch", response: []float64{0, -80}, want: responseMaxSpanDb},
+			{name: "should cap the span for an infinite attenuation", response: []float64{math.Inf(-1)}, want: responseMaxSpanDb},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				got := responseSpan(tt.response)
+
+				if got != tt.want {
+					t.Errorf("got %v dB, want %v", got, tt.want)
+				}
+			})
+		}
+	})
+}
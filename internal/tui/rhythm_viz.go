@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -12,43 +13,46 @@ const (
 	beatsToShow = 4
 
 	hitMarkerDecay = 500 * time.Millisecond
+
+	maxCellsPerBeat = 64
 )
 
 type hitMarker struct {
-	slotIndex int
+	position  float64
 	timestamp time.Time
 }
 
 type rhythmVisualizer struct {
-	bpm         float64
-	subdivision rhythm.Subdivision
-	phase       float64
-	beatCount   int64
-	hitMarkers  []hitMarker
-	width       int
+	bpm           float64
+	grid          rhythm.Grid
+	phase         float64
+	beatCount     int64
+	slotPositions []float64
+	hitMarkers    []hitMarker
+	width         int
 }
 
 func newRhythmVisualizer() rhythmVisualizer {
 	return rhythmVisualizer{
-		bpm:         rhythm.DefaultBPM,
-		subdivision: rhythm.Sub8,
-		hitMarkers:  make([]hitMarker, 0),
-		width:       80,
+		bpm:        rhythm.DefaultBPM,
+		grid:       rhythm.Grid{Subdivision: rhythm.Sub8, Feel: rhythm.FeelStraight, Swing: rhythm.MinSwing},
+		hitMarkers: make([]hitMarker, 0),
+		width:      80,
 	}
 }
 
-func (v *rhythmVisualizer) Update(bpm float64, sub rhythm.Subdivision, phase float64, beatCount int64) {
+func (v *rhythmVisualizer) Update(bpm float64, grid rhythm.Grid, phase float64, beatCount int64, slotPositions []float64) {
 	v.bpm = bpm
-	v.subdivision = sub
+	v.grid = grid
 	v.phase = phase
 	v.beatCount = beatCount
+	v.slotPositions = slotPositions
 	v.cleanupExpiredMarkers()
 }
 
-func (v *rhythmVisualizer) AddHitMarker(beatInDisplay int, slotInBeat int) {
-	globalSlot := beatInDisplay*int(v.subdivision) + slotInBeat
+func (v *rhythmVisualizer) AddHitMarker(beat float64) {
 	v.hitMarkers = append(v.hitMarkers, hitMarker{
-		slotIndex: globalSlot,
+		position:  math.Mod(beat, beatsToShow),
 		timestamp: time.Now(),
 	})
 }
@@ -64,9 +68,9 @@ func (v *rhythmVisualizer) cleanupExpiredMarkers() {
 	v.hitMarkers = active
 }
 
-func (v *rhythmVisualizer) isSlotHit(globalSlot int) bool {
+func (v *rhythmVisualizer) isCellHit(cell, cellsPerBeat int) bool {
 	for _, m := range v.hitMarkers {
-		if m.slotIndex == globalSlot {
+		if positionToCell(m.position, cellsPerBeat) == cell {
 			return true
 		}
 	}
@@ -77,6 +81,48 @@ func (v *rhythmVisualizer) SetWidth(width int) {
 	v.width = width
 }
 
+func (v *rhythmVisualizer) cellsPerBeat() int {
+	limit := min(maxCellsPerBeat, (v.width-4)/beatsToShow)
+	if limit < 1 {
+		limit = 1
+	}
+
+	for cells := 1; cells <= limit; cells++ {
+		if v.slotsAlignTo(cells) {
+			return cells
+		}
+	}
+
+	minGap := 1.0
+	for i := 1; i < len(v.slotPositions); i++ {
+		minGap = math.Min(minGap, v.slotPositions[i]-v.slotPositions[i-1])
+	}
+	if minGap <= 0 {
+		return limit
+	}
+	return min(limit, int(math.Ceil(4/minGap)))
+}
+
+func (v *rhythmVisualizer) slotsAlignTo(cellsPerBeat int) bool {
+	seen := make(map[int]bool, len(v.slotPositions))
+	for _, pos := range v.slotPositions {
+		scaled := pos * float64(cellsPerBeat)
+		if math.Abs(scaled-math.Round(scaled)) > 0.02 {
+			return false
+		}
+		cell := int(math.Round(scaled))
+		if seen[cell] {
+			return false
+		}
+		seen[cell] = true
+	}
+	return true
+}
+
+func positionToCell(position float64, cellsPerBeat int) int {
+	return int(math.Round(position * float64(cellsPerBeat)))
+}
+
 func (v *rhythmVisualizer) View() string {
 	if v.width < 40 {
 		return ""
@@ -84,39 +130,43 @@ func (v *rhythmVisualizer) View() string {
 
 	var sb strings.Builder
 
-	sb.WriteString(fmt.Sprintf(" BPM: %-3.0f  [%s]  TAP:[t]  +/-:[,/.]  Sub:[/]\n",
-		v.bpm, v.subdivision.String()))
+	sb.WriteString(fmt.Sprintf(" BPM: %-3.0f  [%s]  TAP:[t]  +/-:[,/.]  Grid:[/]  Swing:{/}\n",
+		v.bpm, v.grid.String()))
 
-	totalSlots := beatsToShow * int(v.subdivision)
+	cellsPerBeat := v.cellsPerBeat()
+	totalCells := beatsToShow * cellsPerBeat
 
-	sb.WriteString(" ")
-	for beat := 0; beat < beatsToShow; beat++ {
-		for slot := 0; slot < int(v.subdivision); slot++ {
-			globalSlot := beat*int(v.subdivision) + slot
+	slotCells := make(map[int]bool, len(v.slotPositions))
+	for _, pos := range v.slotPositions {
+		slotCells[positionToCell(pos, cellsPerBeat)] = true
+	}
 
-			if slot == 0 {
+	sb.WriteString(" ")
+	for cell := 0; cell < totalCells; cell++ {
+		switch {
+		case cell%cellsPerBeat == 0:
 
-				sb.WriteString("|")
-			} else if v.isSlotHit(globalSlot) {
+			sb.WriteString("|")
+		case slotCells[cell] && v.isCellHit(cell, cellsPerBeat):
 
-				sb.WriteString("!")
-			} else {
+			sb.WriteString("!")
+		case slotCells[cell]:
 
-				sb.WriteString(".")
-			}
+			sb.WriteString(".")
+		default:
+			sb.WriteString(" ")
 		}
 	}
 	sb.WriteString("|\n")
 
 	currentBeatInDisplay := int(v.beatCount) % beatsToShow
-	slotInBeat := int(v.phase * float64(v.subdivision))
-	if slotInBeat >= int(v.subdivision) {
-		slotInBeat = int(v.subdivision) - 1
+	playheadPos := int((float64(currentBeatInDisplay) + v.phase) * float64(cellsPerBeat))
+	if playheadPos >= totalCells {
+		playheadPos = totalCells - 1
 	}
-	playheadPos := currentBeatInDisplay*int(v.subdivision) + slotInBeat
 
 	sb.WriteString(" ")
-	for i := 0; i <= totalSlots; i++ {
+	for i := 0; i <= totalCells; i++ {
 		if i == playheadPos {
 			sb.WriteString("^")
 		} else {
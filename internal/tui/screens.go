@@ -8,4 +8,9 @@ const (
 	ScreenPresetCreate
 	ScreenPresetEdit
 	ScreenEffectAdd
+	ScreenPatternRecorder
+	ScreenDevicePicker
+	ScreenSettings
+	ScreenLatency
+	ScreenResponse
 )
@@ -0,0 +1,163 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/chloyka/gorig/internal/audio"
+)
+
+type settingsField int
+
+const (
+	settingsSampleRate settingsField = iota
+	settingsBufferSize
+	settingsLatency
+	settingsFieldCount
+)
+
+var (
+	settingsSampleRates = []int{22050, 32000, 44100, 48000, 88200, 96000, 192000}
+
+	settingsBufferSizes = []int{16, 32, 64, 128, 256, 512, 1024, 2048}
+
+	settingsLatencies = []time.Duration{
+		2 * time.Millisecond,
+		5 * time.Millisecond,
+		10 * time.Millisecond,
+		20 * time.Millisecond,
+		50 * time.Millisecond,
+		100 * time.Millisecond,
+	}
+)
+
+type SettingsAppliedMsg struct {
+	Err error
+}
+
+type settingsModel struct {
+	audioEngine *audio.Engine
+
+	cursor     settingsField
+	sampleRate int
+	bufferSize int
+	latency    time.Duration
+
+	applying  bool
+	lastError error
+}
+
+func newSettingsModel(engine *audio.Engine) settingsModel {
+	sampleRate, bufferSize, latency := engine.StreamSettings()
+
+	return settingsModel{
+		audioEngine: engine,
+		sampleRate:  sampleRate,
+		bufferSize:  bufferSize,
+		latency:     latency,
+	}
+}
+
+func applySettingsCmd(engine *audio.Engine, sampleRate, bufferSize int, latency time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		return SettingsAppliedMsg{Err: engine.SetStreamSettings(sampleRate, bufferSize, latency)}
+	}
+}
+
+func (m settingsModel) Update(msg tea.Msg) (settingsModel, tea.Cmd, Screen) {
+	switch msg := msg.(type) {
+	case SettingsAppliedMsg:
+		m.applying = false
+		m.lastError = msg.Err
+		if msg.Err == nil {
+			return m, nil, ScreenMain
+		}
+	case tea.KeyMsg:
+		if m.applying {
+			break
+		}
+
+		key := msg.String()
+
+		switch {
+		case MatchKey(key, ActionUp):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+		case MatchKey(key, ActionDown):
+			if m.cursor < settingsFieldCount-1 {
+				m.cursor++
+			}
+		case MatchKey(key, ActionLeft):
+			m.step(-1)
+		case MatchKey(key, ActionRight):
+			m.step(1)
+		case MatchKey(key, ActionEnter):
+			m.applying = true
+			m.lastError = nil
+			return m, applySettingsCmd(m.audioEngine, m.sampleRate, m.bufferSize, m.latency), ScreenSettings
+		case MatchKey(key, ActionEsc):
+			return m, nil, ScreenMain
+		}
+	}
+	return m, nil, ScreenSettings
+}
+
+func (m *settingsModel) step(delta int) {
+	switch m.cursor {
+	case settingsSampleRate:
+		m.sampleRate = stepOption(settingsSampleRates, m.sampleRate, delta)
+	case settingsBufferSize:
+		m.bufferSize = stepOption(settingsBufferSizes, m.bufferSize, delta)
+	case settingsLatency:
+		m.latency = stepOption(settingsLatencies, m.latency, delta)
+	}
+}
+
+func stepOption[T int | time.Duration](options []T, current T, delta int) T {
+	i, found := slices.BinarySearch(options, current)
+	if !found && delta > 0 {
+		i--
+	}
+	i = max(0, min(i+delta, len(options)-1))
+	return options[i]
+}
+
+func (m settingsModel) View() string {
+	var b strings.Builder
+
+	b.WriteString("\n Audio Settings\n")
+	b.WriteString(" ==============\n\n")
+
+	rows := []struct {
+		label string
+		value string
+	}{
+		{"Sample rate", fmt.Sprintf("%d Hz", m.sampleRate)},
+		{"Buffer size", fmt.Sprintf("%d frames", m.bufferSize)},
+		{"Latency", fmt.Sprintf("%.0f ms", m.latency.Seconds()*1000)},
+	}
+
+	for i, row := range rows {
+		cursor := "  "
+		if settingsField(i) == m.cursor {
+			cursor = "> "
+		}
+		b.WriteString(fmt.Sprintf(" %s%-12s < %s >\n", cursor, row.label, row.value))
+	}
+
+	b.WriteString(fmt.Sprintf("\n Running: %s\n", formatStreamFormat(m.audioEngine.StreamFormat())))
+
+	if m.applying {
+		b.WriteString("\n Applying, restarting stream...\n")
+	} else if m.lastError != nil {
+		b.WriteString(fmt.Sprintf("\n Apply failed: %v\n", m.lastError))
+	}
+
+	b.WriteString("\n [j/k] Move  [h/l] Change  [enter] Apply  [esc] Back\n")
+
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	errs "github.com/chloyka/gorig/utils/errors"
+)
+
+func TestSettingsModel(t *testing.T) {
+	t.Run("Update", func(t *testing.T) {
+		t.Run("should apply settings in a command", func(t *testing.T) {
+			sut := settingsModel{sampleRate: 48000, bufferSize: 128}
+
+			got, cmd, screen := sut.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+			if !got.applying || cmd == nil || screen != ScreenSettings {
+				t.Errorf("got applying=%v cmd=%v screen=%v, want pending apply on settings screen", got.applying, cmd != nil, screen)
+			}
+		})
+
+		t.Run("should ignore keys while applying", func(t *testing.T) {
+			sut := settingsModel{applying: true}
+
+			_, _, got := sut.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+			if got != ScreenSettings {
+				t.Errorf("got screen %v, want settings", got)
+			}
+		})
+
+		t.Run("should return to main screen after successful apply", func(t *testing.T) {
+			sut := settingsModel{applying: true}
+
+			_, _, got := sut.Update(SettingsAppliedMsg{})
+
+			if got != ScreenMain {
+				t.Errorf("got screen %v, want main", got)
+			}
+		})
+
+		t.Run("should stay and report failed apply", func(t *testing.T) {
+			sut := settingsModel{applying: true}
+
+			got, _, screen := sut.Update(SettingsAppliedMsg{Err: errs.ErrAudioFormat})
+
+			if screen != ScreenSettings || got.applying || got.lastError == nil {
+				t.Errorf("got screen=%v applying=%v err=%v, want settings with error", screen, got.applying, got.lastError)
+			}
+		})
+	})
+}
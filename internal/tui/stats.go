@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chloyka/gorig/internal/audio"
+)
+
+func renderStatsPanel(stats audio.Stats) string {
+	var b strings.Builder
+
+	b.WriteString("\n Performance:\n")
+	fmt.Fprintf(&b, "   DSP load: %5.1f%%  (peak %5.1f%%)  buffer %s\n",
+		stats.Load, stats.PeakLoad, formatMillis(stats.BufferDuration))
+	fmt.Fprintf(&b, "   Xruns:    in %d/%d  out %d/%d  (underflow/overflow)\n",
+		stats.InputUnderflows, stats.InputOverflows, stats.OutputUnderflows, stats.OutputOverflows)
+	fmt.Fprintf(&b, "   Latency:  in %s  out %s  chain %s\n",
+		formatMillis(stats.InputLatency), formatMillis(stats.OutputLatency), formatMillis(stats.ChainLatency))
+
+	for _, timing := range stats.Effects {
+		load := 0.0
+		if stats.BufferDuration > 0 {
+			load = float64(timing.Average) / float64(stats.BufferDuration) * 100
+		}
+		fmt.Fprintf(&b, "   %-16s %7.1f µs  peak %7.1f µs  %5.1f%%\n",
+			timing.Name, micros(timing.Average), micros(timing.Peak), load)
+	}
+
+	return b.String()
+}
+
+func formatMillis(d time.Duration) string {
+	return fmt.Sprintf("%.1f ms", float64(d)/float64(time.Millisecond))
+}
+
+func micros(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
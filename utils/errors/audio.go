@@ -7,4 +7,11 @@ var (
 	ErrAudioOpenStream  = New("audio: failed to open stream")
 	ErrAudioStartStream = New("audio: failed to start stream")
 	ErrAudioTerminate   = New("audio: failed to terminate portaudio")
+	ErrAudioDeviceIndex = New("audio: device index out of range")
+	ErrAudioFormat      = New("audio: no supported stream format")
+
+	ErrAudioStreamStopped   = New("audio: stream is not running")
+	ErrAudioLatencyBusy     = New("audio: latency measurement already running")
+	ErrAudioLatencyTimeout  = New("audio: latency measurement timed out")
+	ErrAudioLatencyNoSignal = New("audio: no loopback signal detected")
 )
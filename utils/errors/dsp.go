@@ -0,0 +1,6 @@
+package errors
+
+var (
+	ErrWAVRead   = New("dsp: failed to read wav file")
+	ErrWAVFormat = New("dsp: unsupported wav format")
+)
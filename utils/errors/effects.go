@@ -9,4 +9,6 @@ var (
 	ErrEffectsGetProcess    = New("effects: failed to get Process")
 	ErrEffectsDuplicateName = New("effects: duplicate effect name")
 	ErrEffectsLoad          = New("effects: failed to load")
+	ErrEffectsNotFound      = New("effects: effect not found")
+	ErrEffectsIRPath        = New("effects: IR path outside IR directory")
 )
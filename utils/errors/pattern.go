@@ -0,0 +1,7 @@
+package errors
+
+var (
+	ErrPatternEmpty     = New("pattern: nothing recorded")
+	ErrPatternCreateDir = New("pattern: failed to create directory")
+	ErrPatternWrite     = New("pattern: failed to write file")
+)